@@ -0,0 +1,112 @@
+package unsafer
+
+import (
+	"unsafe"
+)
+
+/*********************************************************************************
+	THE FOLLOWING FUNCTIONS AND TYPES ARE ADDED BY THE AUTHOR TO MAKE USE OF THE
+	TYPES IN unsafer.go IN NEW, INTERESTING, AND POSSIBLY UNSAFER WAYS, LICENSED
+	UNDER THE PERMISIVE BSD 2-CLAUSE LICENSE.
+*********************************************************************************/
+
+// Default size in bytes of each block of memory an Arena allocates from
+const arenaChunkSize = 64 << 10
+
+// A bump allocator for values whose types are only known by type pointer at runtime.
+// Values are carved out of large blocks of memory, amortizing the cost of allocation,
+// and all of them are released together with Reset.
+// The zero value is an empty arena ready to use. An Arena is NOT safe for concurrent use.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+type Arena struct {
+	chunks  [][]byte // Blocks of memory values are allocated from, kept alive by the arena
+	current int      // Index of the chunk currently being allocated from
+	offset  int      // Offset of the first free byte in the current chunk
+}
+
+// Allocate zeroed memory for a single value of the type located at typePointer,
+// aligned to the type's Align, and return a pointer to it.
+// Combine with Invent to create a value of the type.
+// Use GetTypePointer(t any) to find type pointer addresses.
+//
+// The arena's blocks are invisible to the garbage collector as far as pointers are concerned,
+// so types containing pointers are allocated individually outside the arena instead,
+// keeping the pointers they hold visible.
+//
+// Memory returned by New MUST NOT be used after the next call to Reset.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func (a *Arena) New(typePointer uintptr) unsafe.Pointer {
+	t := typeAt(typePointer)
+	if t.PtrData != 0 || t.Size == 0 {
+		return unsafeNew(t)
+	}
+	return a.alloc(int(t.Size), int(t.Align))
+}
+
+// Return a pointer to size zeroed bytes aligned to align, adding a new chunk if needed
+func (a *Arena) alloc(size, align int) unsafe.Pointer {
+	for {
+		if a.current < len(a.chunks) {
+			chunk := a.chunks[a.current]
+			base := uintptr(unsafe.Pointer(&chunk[0]))
+			padding := int(alignPadding(base+uintptr(a.offset), uintptr(align)))
+			if start := a.offset + padding; start+size <= len(chunk) {
+				a.offset = start + size
+				value := chunk[start:a.offset]
+				for i := range value {
+					value[i] = 0
+				}
+				return unsafe.Pointer(&value[0])
+			}
+			if a.current+1 < len(a.chunks) {
+				a.current++
+				a.offset = 0
+				continue
+			}
+		}
+		chunkSize := arenaChunkSize
+		if size > chunkSize {
+			chunkSize = size
+		}
+		// The new chunk starts aligned to align, so the value always fits at its start
+		chunk := SliceFromPointer[byte](AllocAligned(uintptr(chunkSize), uintptr(align)), chunkSize)
+		a.chunks = append(a.chunks, chunk)
+		a.current = len(a.chunks) - 1
+		a.offset = 0
+	}
+}
+
+// Return how many bytes must be added to addr to align it to align, which must be a power of two
+func alignPadding(addr, align uintptr) uintptr {
+	return -addr & (align - 1)
+}
+
+// Release all values allocated by the arena at once, so their memory can be reused
+// by future calls to New. The arena keeps its blocks of memory for reuse.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func (a *Arena) Reset() {
+	a.current = 0
+	a.offset = 0
+}
+
+// Allocate size zeroed bytes of memory aligned to align, and return a pointer to them.
+// The memory is kept alive for as long as the returned pointer (or any pointer into it)
+// is reachable.
+//
+// The memory is invisible to the garbage collector as far as pointers are concerned,
+// so it MUST NOT be used to hold the only reference to any Go-allocated object.
+//
+// Panics if align is not a power of two.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func AllocAligned(size, align uintptr) unsafe.Pointer {
+	if align == 0 || align&(align-1) != 0 {
+		panic("unsafer: AllocAligned with alignment that is not a power of two")
+	}
+	backing := make([]byte, size+align)
+	base := unsafe.Pointer(&backing[0])
+	return unsafe.Add(base, alignPadding(uintptr(base), align))
+}
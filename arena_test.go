@@ -0,0 +1,72 @@
+package unsafer
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestArena(t *testing.T) {
+	var a Arena
+	typePointers := []uintptr{
+		TypePointerOf[uint8](),
+		TypePointerOf[int64](),
+		TypePointerOf[[3]byte](),
+		TypePointerOf[uint16](),
+		TypePointerOf[complex128](),
+		TypePointerOf[rawStruct](),
+	}
+	check := func(round int) {
+		for i := 0; i < 10000; i++ {
+			typ := typeAt(typePointers[i%len(typePointers)])
+			p := a.New(uintptr(unsafe.Pointer(typ)))
+			if uintptr(p)%uintptr(typ.Align) != 0 {
+				t.Fatalf("round %d: New(%s) = %p, not aligned to %d", round, typ.nameWithoutExtraStar(), p, typ.Align)
+			}
+			value := SliceFromPointer[byte](p, int(typ.Size))
+			for j, b := range value {
+				if b != 0 {
+					t.Fatalf("round %d: New(%s) byte %d = %#x, not zeroed", round, typ.nameWithoutExtraStar(), j, b)
+				}
+				value[j] = 0xFF
+			}
+		}
+	}
+	check(0)
+	chunks := len(a.chunks)
+	if chunks < 2 {
+		t.Errorf("allocating %d values used only %d chunk", 10000, chunks)
+	}
+	a.Reset()
+	check(1)
+	if len(a.chunks) != chunks {
+		t.Errorf("Arena grew from %d to %d chunks after Reset", chunks, len(a.chunks))
+	}
+	if v := Invent(a.New(TypePointerOf[int64]()), TypePointerOf[int64]()); v != int64(0) {
+		t.Errorf("Invent of an arena value = %v", v)
+	}
+	big := typeAt(TypePointerOf[[arenaChunkSize * 2]byte]())
+	if p := a.New(uintptr(unsafe.Pointer(big))); p == nil {
+		t.Error("New of a value larger than a chunk returned nil")
+	}
+	if p := a.New(TypePointerOf[*int]()); *(**int)(p) != nil {
+		t.Error("New of a pointer type was not zeroed")
+	}
+}
+
+func TestAllocAligned(t *testing.T) {
+	for _, align := range []uintptr{1, 2, 4, 8, 16, 64, 4096} {
+		for _, size := range []uintptr{0, 1, 7, 100} {
+			p := AllocAligned(size, align)
+			if uintptr(p)%align != 0 {
+				t.Errorf("AllocAligned(%d, %d) = %p, not aligned", size, align, p)
+			}
+			for i, b := range SliceFromPointer[byte](p, int(size)) {
+				if b != 0 {
+					t.Fatalf("AllocAligned(%d, %d) byte %d = %#x, not zeroed", size, align, i, b)
+				}
+			}
+		}
+	}
+	expectPanic(t, "AllocAligned with align 3", func() { AllocAligned(8, 3) })
+	expectPanic(t, "AllocAligned with align 0", func() { AllocAligned(8, 0) })
+}
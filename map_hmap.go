@@ -0,0 +1,184 @@
+//go:build !go1.26 && !goexperiment.swissmap
+
+package unsafer
+
+import "unsafe"
+
+const (
+	IndirectKey    MapTypeFlag = 1  // Key cells hold a pointer to the key rather than the key itself
+	IndirectElem   MapTypeFlag = 2  // Value cells hold a pointer to the value rather than the value itself
+	ReflexiveKey   MapTypeFlag = 4  // k == k for all keys of this type
+	NeedKeyUpdate  MapTypeFlag = 8  // Overwriting a value requires overwriting the key as well
+	HashMightPanic MapTypeFlag = 16 // The hash function might panic (for example, interface keys)
+)
+
+// TypeInternal wrapper for maps
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+type MapTypeInternal struct {
+	Type       TypeInternal                          // Basic type data for the map
+	Key        *TypeInternal                         // The type of the map's keys
+	Elem       *TypeInternal                         // The type of the map's values
+	Bucket     *TypeInternal                         // Internal type representing a bucket of this map
+	Hasher     func(unsafe.Pointer, uintptr) uintptr // Function for hashing keys: (pointer to key, seed) -> hash
+	KeySize    uint8                                 // Size of a key cell in a bucket
+	ElemSize   uint8                                 // Size of a value cell in a bucket
+	BucketSize uint16                                // Size of a bucket, including the trailing overflow pointer
+	Flags      MapTypeFlag                           // Flags describing how keys and values are stored
+}
+
+// The runtime's internal structure of a map value, on this runtime
+type mapHeader = MapInternal
+
+// Return the number of Key-Value pairs currently active in the map
+func (mi *MapInternal) count() int {
+	return mi.Count
+}
+
+// Return the flags describing special states of the map
+func (mi *MapInternal) flags() MapFlag {
+	return mi.Flags
+}
+
+// Return the number of buckets in the map, and the (approximate) number of overflow buckets
+func (mi *MapInternal) bucketStats() (buckets int, overflowApprox int) {
+	return 1 << mi.NumBucketsLog2, int(mi.NumOverflow)
+}
+
+// Return the size of the key and value cells in the buckets of map type mt
+func (mt *MapTypeInternal) cellSizes() (keySize, valueSize uintptr) {
+	return uintptr(mt.KeySize), uintptr(mt.ElemSize)
+}
+
+// Return the number of bytes allocated for the header and buckets of the map with type mt
+// and internals mi, estimated from its bucket counts
+func mapStorageSize(mt *MapTypeInternal, mi *MapInternal) uintptr {
+	numBuckets := uintptr(1)<<mi.NumBucketsLog2 + uintptr(mi.NumOverflow)
+	if mi.OldBuckets != nil {
+		numBuckets += uintptr(1) << mi.NumBucketsLog2 >> 1
+	}
+	return unsafe.Sizeof(MapInternal{}) + numBuckets*uintptr(mt.BucketSize)
+}
+
+// Return the offsets from the start of a bucket of map type mt to its first key cell,
+// its first value cell, and its overflow pointer
+func (mt *MapTypeInternal) bucketLayout() (keyStart, valueStart, overflowOffset uintptr) {
+	keyAlign, valueAlign := uintptr(mt.Key.Align), uintptr(mt.Elem.Align)
+	if mt.Flags&IndirectKey != 0 {
+		keyAlign = SystemPointerSize
+	}
+	if mt.Flags&IndirectElem != 0 {
+		valueAlign = SystemPointerSize
+	}
+	keyStart = unsafe.Sizeof(BucketInternal{})
+	keyStart += alignPadding(keyStart, keyAlign)
+	valueStart = keyStart + BucketSize*uintptr(mt.KeySize)
+	valueStart += alignPadding(valueStart, valueAlign)
+	return keyStart, valueStart, uintptr(mt.BucketSize) - SystemPointerSize
+}
+
+// Call fn with pointers to every valid key/value pair in the bucket at bucket
+// and its chain of overflow buckets, stopping early if fn returns false.
+// Returns false if iteration was stopped early.
+func rangeBucketChain(mt *MapTypeInternal, bucket unsafe.Pointer, fn func(keyPtr, valuePtr unsafe.Pointer) bool) bool {
+	keysStart, valuesStart, overflowOffset := mt.bucketLayout()
+	for ; bucket != nil; bucket = *(*unsafe.Pointer)(unsafe.Add(bucket, overflowOffset)) {
+		b := (*BucketInternal)(bucket)
+		for i := uintptr(0); i < BucketSize; i++ {
+			if b.TopHash[i] == LastEmptyCell {
+				return true
+			}
+			if b.TopHash[i] < MinimumTopHash {
+				continue
+			}
+			keyPtr := unsafe.Add(bucket, keysStart+i*uintptr(mt.KeySize))
+			if mt.Flags&IndirectKey != 0 {
+				keyPtr = *(*unsafe.Pointer)(keyPtr)
+			}
+			valuePtr := unsafe.Add(bucket, valuesStart+i*uintptr(mt.ElemSize))
+			if mt.Flags&IndirectElem != 0 {
+				valuePtr = *(*unsafe.Pointer)(valuePtr)
+			}
+			if !fn(keyPtr, valuePtr) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Call fn with pointers to each key and value in the map with type mt and internals mi,
+// stopping early if fn returns false
+func rangeMap(mt *MapTypeInternal, mi *MapInternal, fn func(keyPtr, valuePtr unsafe.Pointer) bool) {
+	if mi == nil || mi.Count == 0 {
+		return
+	}
+	bucketSize := uintptr(mt.BucketSize)
+	if mi.OldBuckets != nil {
+		numOld := uintptr(1) << mi.NumBucketsLog2
+		if mi.Flags&GrowingToSameSize == 0 {
+			numOld >>= 1
+		}
+		for i := uintptr(0); i < numOld; i++ {
+			if !rangeBucketChain(mt, unsafe.Add(mi.OldBuckets, i*bucketSize), fn) {
+				return
+			}
+		}
+	}
+	numBuckets := uintptr(1) << mi.NumBucketsLog2
+	for i := uintptr(0); i < numBuckets; i++ {
+		if !rangeBucketChain(mt, unsafe.Add(mi.Buckets, i*bucketSize), fn) {
+			return
+		}
+	}
+}
+
+// Return a pointer to the value stored under the key pointed to by keyPtr in the map with
+// type mt and internals mi, or nil if the key is not present
+func mapLookup(mt *MapTypeInternal, mi *MapInternal, keyPtr unsafe.Pointer) unsafe.Pointer {
+	if mi == nil || mi.Count == 0 {
+		return nil
+	}
+	hash := mt.Hasher(keyPtr, uintptr(mi.HashSeed))
+	mask := uintptr(1)<<mi.NumBucketsLog2 - 1
+	bucketSize := uintptr(mt.BucketSize)
+	bucket := unsafe.Add(mi.Buckets, (hash&mask)*bucketSize)
+	if mi.OldBuckets != nil {
+		if mi.Flags&GrowingToSameSize == 0 {
+			mask >>= 1
+		}
+		oldBucket := unsafe.Add(mi.OldBuckets, (hash&mask)*bucketSize)
+		if _, evacuated, _ := ClassifyTopHash((*BucketInternal)(oldBucket).TopHash[0]); !evacuated {
+			bucket = oldBucket
+		}
+	}
+	top := uint8(hash >> (SystemPointerSize*8 - 8))
+	if top < MinimumTopHash {
+		top += MinimumTopHash
+	}
+	keysStart, valuesStart, overflowOffset := mt.bucketLayout()
+	for ; bucket != nil; bucket = *(*unsafe.Pointer)(unsafe.Add(bucket, overflowOffset)) {
+		b := (*BucketInternal)(bucket)
+		for i := uintptr(0); i < BucketSize; i++ {
+			if b.TopHash[i] != top {
+				if b.TopHash[i] == LastEmptyCell {
+					return nil
+				}
+				continue
+			}
+			cellKey := unsafe.Add(bucket, keysStart+i*uintptr(mt.KeySize))
+			if mt.Flags&IndirectKey != 0 {
+				cellKey = *(*unsafe.Pointer)(cellKey)
+			}
+			if !mt.Key.Equals(keyPtr, cellKey) {
+				continue
+			}
+			cellValue := unsafe.Add(bucket, valuesStart+i*uintptr(mt.ElemSize))
+			if mt.Flags&IndirectElem != 0 {
+				cellValue = *(*unsafe.Pointer)(cellValue)
+			}
+			return cellValue
+		}
+	}
+	return nil
+}
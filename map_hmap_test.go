@@ -0,0 +1,46 @@
+//go:build !go1.26 && !goexperiment.swissmap
+
+package unsafer
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestBucketLayout(t *testing.T) {
+	var bucket struct {
+		TopHash  [BucketSize]uint8
+		Keys     [BucketSize]uint8
+		Values   [BucketSize]complex128
+		Overflow unsafe.Pointer
+	}
+	keyStart, valueStart, overflowOffset := BucketLayout(map[uint8]complex128{})
+	if keyStart != unsafe.Offsetof(bucket.Keys) || valueStart != unsafe.Offsetof(bucket.Values) || overflowOffset != unsafe.Offsetof(bucket.Overflow) {
+		t.Errorf("BucketLayout(map[uint8]complex128) = %d, %d, %d, want %d, %d, %d", keyStart, valueStart, overflowOffset,
+			unsafe.Offsetof(bucket.Keys), unsafe.Offsetof(bucket.Values), unsafe.Offsetof(bucket.Overflow))
+	}
+	m := map[uint8]complex128{}
+	for i := 0; i < 8; i++ {
+		m[uint8(i)] = complex(float64(i), -float64(i))
+	}
+	RangeMapRaw(m, func(keyPtr, valuePtr unsafe.Pointer) bool {
+		if m[*(*uint8)(keyPtr)] != *(*complex128)(valuePtr) {
+			t.Errorf("RangeMapRaw of map[uint8]complex128 mis-addressed key %d", *(*uint8)(keyPtr))
+		}
+		return true
+	})
+}
+
+func TestMapFlagsBeingWrittenTo(t *testing.T) {
+	m := map[int]int{1: 1}
+	mi := getMapInternal(m, "TestMapFlagsBeingWrittenTo")
+	mi.Flags |= BeingWrittenTo
+	flags := MapFlags(m)
+	mi.Flags &^= BeingWrittenTo
+	if flags&BeingWrittenTo == 0 {
+		t.Errorf("MapFlags of a map being written to = %d, missing BeingWrittenTo", flags)
+	}
+	if flags := MapFlags(m); flags&BeingWrittenTo != 0 {
+		t.Errorf("MapFlags after the write finished = %d, still has BeingWrittenTo", flags)
+	}
+}
@@ -0,0 +1,201 @@
+//go:build go1.26 || goexperiment.swissmap
+
+package unsafer
+
+import "unsafe"
+
+const (
+	NeedKeyUpdate  MapTypeFlag = 1 // Overwriting a value requires overwriting the key as well
+	HashMightPanic MapTypeFlag = 2 // The hash function might panic (for example, interface keys)
+	IndirectKey    MapTypeFlag = 4 // Key cells hold a pointer to the key rather than the key itself
+	IndirectElem   MapTypeFlag = 8 // Value cells hold a pointer to the value rather than the value itself
+	ReflexiveKey   MapTypeFlag = 0 // Not recorded by Swiss-table map types, so never reported as set
+)
+
+// The runtime's internal structure of a map value, on this runtime
+type mapHeader = SwissMapInternal
+
+// Return the number of Key-Value pairs currently active in the map
+func (mi *SwissMapInternal) count() int {
+	return int(mi.Used)
+}
+
+// Return the flags describing special states of the map.
+// Swiss tables have no iterator or grow states, so only BeingWrittenTo can be reported.
+func (mi *SwissMapInternal) flags() MapFlag {
+	if mi.Writing != 0 {
+		return BeingWrittenTo
+	}
+	return 0
+}
+
+// Return the table referred to by directory entry i of a large map
+func (mi *SwissMapInternal) tableAt(i uintptr) *SwissTableInternal {
+	return *(**SwissTableInternal)(unsafe.Add(mi.Directory, i*SystemPointerSize))
+}
+
+// Call fn with each distinct table of a large map, stopping early if fn returns false.
+// Returns false if iteration was stopped early.
+func (mi *SwissMapInternal) rangeTables(fn func(t *SwissTableInternal) bool) bool {
+	for i := 0; i < mi.DirectoryLen; i++ {
+		t := mi.tableAt(uintptr(i))
+		if t.Index != i {
+			continue // Shared with an earlier directory entry, already visited
+		}
+		if !fn(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return the number of groups in the map. Swiss tables never allocate overflow storage,
+// so the overflow count is always 0.
+func (mi *SwissMapInternal) bucketStats() (buckets int, overflowApprox int) {
+	if mi.DirectoryLen == 0 {
+		if mi.Directory == nil {
+			return 0, 0
+		}
+		return 1, 0
+	}
+	mi.rangeTables(func(t *SwissTableInternal) bool {
+		buckets += int(t.GroupsMask) + 1
+		return true
+	})
+	return buckets, 0
+}
+
+// Swiss-table maps have no buckets, so no bucket layout can be returned
+func (mt *MapTypeInternal) bucketLayout() (keyStart, valueStart, overflowOffset uintptr) {
+	panic("unsafer: BucketLayout of map on a Swiss-table runtime, which has no buckets")
+}
+
+// Return the size of the key and value cells in the groups of map type mt
+func (mt *MapTypeInternal) cellSizes() (keySize, valueSize uintptr) {
+	keySize, valueSize = mt.Key.Size, mt.Elem.Size
+	if mt.Flags&IndirectKey != 0 {
+		keySize = SystemPointerSize
+	}
+	if mt.Flags&IndirectElem != 0 {
+		valueSize = SystemPointerSize
+	}
+	return keySize, valueSize
+}
+
+// Return the control byte of slot i of the group at group.
+// The control word is read whole, so slot i is always its i-th lowest byte regardless of endianness.
+func groupCtrl(group unsafe.Pointer, i uintptr) uint8 {
+	return uint8(*(*uint64)(group) >> (8 * i))
+}
+
+// Return pointers to the key and value of slot i of the group at group,
+// following indirect cells of map type mt
+func (mt *MapTypeInternal) slotPointers(group unsafe.Pointer, i uintptr) (keyPtr, valuePtr unsafe.Pointer) {
+	keyPtr, valuePtr = mt.slotCells(group, i)
+	if mt.Flags&IndirectKey != 0 {
+		keyPtr = *(*unsafe.Pointer)(keyPtr)
+	}
+	if mt.Flags&IndirectElem != 0 {
+		valuePtr = *(*unsafe.Pointer)(valuePtr)
+	}
+	return keyPtr, valuePtr
+}
+
+// Call fn with pointers to every key/value pair in the full slots of the group at group,
+// stopping early if fn returns false. Returns false if iteration was stopped early.
+func rangeGroup(mt *MapTypeInternal, group unsafe.Pointer, fn func(keyPtr, valuePtr unsafe.Pointer) bool) bool {
+	for i := uintptr(0); i < SwissGroupSlots; i++ {
+		if groupCtrl(group, i)&SwissCtrlEmpty != 0 {
+			continue // Empty or deleted
+		}
+		if !fn(mt.slotPointers(group, i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Call fn with pointers to each key and value in the map with type mt and internals mi,
+// stopping early if fn returns false
+func rangeMap(mt *MapTypeInternal, mi *SwissMapInternal, fn func(keyPtr, valuePtr unsafe.Pointer) bool) {
+	if mi == nil || mi.Used == 0 {
+		return
+	}
+	if mi.DirectoryLen == 0 {
+		rangeGroup(mt, mi.Directory, fn)
+		return
+	}
+	mi.rangeTables(func(t *SwissTableInternal) bool {
+		for g := uint64(0); g <= t.GroupsMask; g++ {
+			if !rangeGroup(mt, unsafe.Add(t.Groups, uintptr(g)*mt.GroupSize), fn) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Return a pointer to the value stored under the key pointed to by keyPtr among the slots
+// of the group at group whose control byte is h2, or nil if there is none.
+// probeOn reports whether a lookup must continue to the next group in its probe sequence,
+// which is true when the key was not found and the group has no empty slots.
+func lookupGroup(mt *MapTypeInternal, group unsafe.Pointer, h2 uint8, keyPtr unsafe.Pointer) (valuePtr unsafe.Pointer, probeOn bool) {
+	probeOn = true
+	for i := uintptr(0); i < SwissGroupSlots; i++ {
+		switch groupCtrl(group, i) {
+		case SwissCtrlEmpty:
+			probeOn = false
+		case h2:
+			cellKey, cellValue := mt.slotPointers(group, i)
+			if mt.Key.Equals(keyPtr, cellKey) {
+				return cellValue, false
+			}
+		}
+	}
+	return nil, probeOn
+}
+
+// Return a pointer to the value stored under the key pointed to by keyPtr in the map with
+// type mt and internals mi, or nil if the key is not present
+func mapLookup(mt *MapTypeInternal, mi *SwissMapInternal, keyPtr unsafe.Pointer) unsafe.Pointer {
+	if mi == nil || mi.Used == 0 {
+		return nil
+	}
+	hash := mt.Hasher(keyPtr, mi.Seed)
+	h2 := uint8(hash & 0x7f)
+	if mi.DirectoryLen == 0 {
+		valuePtr, _ := lookupGroup(mt, mi.Directory, h2, keyPtr)
+		return valuePtr
+	}
+	var dirIndex uintptr
+	if mi.DirectoryLen > 1 {
+		dirIndex = hash >> (mi.GlobalShift & 63)
+	}
+	t := mi.tableAt(dirIndex)
+	offset := uint64(hash>>7) & t.GroupsMask
+	for step := uint64(1); ; step++ {
+		valuePtr, probeOn := lookupGroup(mt, unsafe.Add(t.Groups, uintptr(offset)*mt.GroupSize), h2, keyPtr)
+		if !probeOn {
+			return valuePtr
+		}
+		offset = (offset + step) & t.GroupsMask
+	}
+}
+
+// Return the number of bytes allocated for the header, directory, tables, and groups
+// of the map with type mt and internals mi
+func mapStorageSize(mt *MapTypeInternal, mi *SwissMapInternal) uintptr {
+	size := unsafe.Sizeof(SwissMapInternal{})
+	if mi.DirectoryLen == 0 {
+		if mi.Directory != nil {
+			size += mt.GroupSize
+		}
+		return size
+	}
+	size += uintptr(mi.DirectoryLen) * SystemPointerSize
+	mi.rangeTables(func(t *SwissTableInternal) bool {
+		size += unsafe.Sizeof(SwissTableInternal{}) + uintptr(t.GroupsMask+1)*mt.GroupSize
+		return true
+	})
+	return size
+}
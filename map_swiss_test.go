@@ -0,0 +1,23 @@
+//go:build go1.26 || goexperiment.swissmap
+
+package unsafer
+
+import "testing"
+
+func TestBucketLayout(t *testing.T) {
+	expectPanic(t, "BucketLayout on a Swiss-table runtime", func() { BucketLayout(map[uint8]complex128{}) })
+}
+
+func TestMapFlagsBeingWrittenTo(t *testing.T) {
+	m := map[int]int{1: 1}
+	mi := getMapInternal(m, "TestMapFlagsBeingWrittenTo")
+	mi.Writing = 1
+	flags := MapFlags(m)
+	mi.Writing = 0
+	if flags != BeingWrittenTo {
+		t.Errorf("MapFlags of a map being written to = %d, want %d", flags, BeingWrittenTo)
+	}
+	if flags := MapFlags(m); flags != 0 {
+		t.Errorf("MapFlags after the write finished = %d, want 0", flags)
+	}
+}
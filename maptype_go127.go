@@ -0,0 +1,28 @@
+//go:build go1.27
+
+package unsafer
+
+import "unsafe"
+
+// TypeInternal wrapper for maps
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+type MapTypeInternal struct {
+	Type       TypeInternal                          // Basic type data for the map
+	Key        *TypeInternal                         // The type of the map's keys
+	Elem       *TypeInternal                         // The type of the map's values
+	Group      *TypeInternal                         // Internal type representing a group of slots of this map
+	Hasher     func(unsafe.Pointer, uintptr) uintptr // Function for hashing keys: (pointer to key, seed) -> hash
+	GroupSize  uintptr                               // Size of a group, equal to Group.Size
+	KeysOff    uintptr                               // Offset from the start of a group to its first key cell
+	KeyStride  uintptr                               // Distance between consecutive key cells in a group
+	ElemsOff   uintptr                               // Offset from the start of a group to its first value cell
+	ElemStride uintptr                               // Distance between consecutive value cells in a group
+	ElemOff    uintptr                               // Offset of the value cell within a slot, when keys and values are interleaved
+	Flags      MapTypeFlag                           // Flags describing how keys and values are stored
+}
+
+// Return pointers to the key and value cells of slot i of the group at group
+func (mt *MapTypeInternal) slotCells(group unsafe.Pointer, i uintptr) (keyCell, valueCell unsafe.Pointer) {
+	return unsafe.Add(group, mt.KeysOff+i*mt.KeyStride), unsafe.Add(group, mt.ElemsOff+i*mt.ElemStride)
+}
@@ -0,0 +1,27 @@
+//go:build !go1.27 && (go1.26 || goexperiment.swissmap)
+
+package unsafer
+
+import "unsafe"
+
+// TypeInternal wrapper for maps
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+type MapTypeInternal struct {
+	Type      TypeInternal                          // Basic type data for the map
+	Key       *TypeInternal                         // The type of the map's keys
+	Elem      *TypeInternal                         // The type of the map's values
+	Group     *TypeInternal                         // Internal type representing a group of slots of this map
+	Hasher    func(unsafe.Pointer, uintptr) uintptr // Function for hashing keys: (pointer to key, seed) -> hash
+	GroupSize uintptr                               // Size of a group, equal to Group.Size
+	SlotSize  uintptr                               // Size of one key/value slot in a group
+	ElemOff   uintptr                               // Offset of the value cell within a slot
+	Flags     MapTypeFlag                           // Flags describing how keys and values are stored
+}
+
+// Return pointers to the key and value cells of slot i of the group at group.
+// Slots follow the group's 8-byte control word.
+func (mt *MapTypeInternal) slotCells(group unsafe.Pointer, i uintptr) (keyCell, valueCell unsafe.Pointer) {
+	keyCell = unsafe.Add(group, 8+i*mt.SlotSize)
+	return keyCell, unsafe.Add(keyCell, mt.ElemOff)
+}
@@ -0,0 +1,58 @@
+package unsafer
+
+import (
+	"sync"
+	"unsafe"
+)
+
+/*********************************************************************************
+	THE FOLLOWING FUNCTIONS AND TYPES ARE ADDED BY THE AUTHOR TO MAKE USE OF THE
+	TYPES IN unsafer.go IN NEW, INTERESTING, AND POSSIBLY UNSAFER WAYS, LICENSED
+	UNDER THE PERMISIVE BSD 2-CLAUSE LICENSE.
+*********************************************************************************/
+
+// A set of free lists of values, one for each type pointer, for reusing the memory
+// of values whose types are only known at runtime. Each free list is a sync.Pool,
+// so pooled values may be released to the garbage collector at any time.
+// The zero value is an empty pool ready to use, and it is safe for concurrent use.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+type TypedPool struct {
+	pools sync.Map // Map from *TypeInternal to the *sync.Pool of unsafe.Pointer storage for that type
+}
+
+// Return an 'any' holding the zero value of the type located at typePointer,
+// reusing the storage of a value previously returned to the pool with Put if one is available.
+// Use GetTypePointer(t any) to find type pointer addresses.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func (p *TypedPool) Get(typePointer uintptr) any {
+	t := typeAt(typePointer)
+	if pool, ok := p.pools.Load(t); ok {
+		if data, ok := pool.(*sync.Pool).Get().(unsafe.Pointer); ok {
+			typedmemclr(t, data)
+			return box(t, data)
+		}
+	}
+	return box(t, unsafeNew(t))
+}
+
+// Return the storage of the value held by v to the pool, for reuse by a later
+// Get of the same type. Values of direct-iface or zero-size types have no storage
+// of their own to reuse, and are ignored, as is a nil v.
+//
+// After Put, v and every copy of it (including pointers into its storage)
+// MUST NOT be used again, as the storage may be handed out by Get at any time.
+//
+// Unsafety Rating: ★★★★☆ (highly dangerous)
+func (p *TypedPool) Put(v any) {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if vv.Type == nil || vv.Type.Size == 0 || vv.Type.IsDirectIface() {
+		return
+	}
+	pool, ok := p.pools.Load(vv.Type)
+	if !ok {
+		pool, _ = p.pools.LoadOrStore(vv.Type, new(sync.Pool))
+	}
+	pool.(*sync.Pool).Put(vv.Data)
+}
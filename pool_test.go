@@ -0,0 +1,57 @@
+package unsafer
+
+import (
+	"testing"
+	"unsafe"
+)
+
+type pooledValue struct {
+	ID   int
+	Name string
+	Data [4]uint64
+}
+
+func TestTypedPool(t *testing.T) {
+	var p TypedPool
+	typePointer := TypePointerOf[pooledValue]()
+	reused := false
+	for i := 0; i < 20 && !reused; i++ {
+		v := p.Get(typePointer)
+		pv, ok := v.(pooledValue)
+		if !ok || pv.ID != 0 || pv.Name != "" || pv.Data != ([4]uint64{}) {
+			t.Fatalf("Get = %+v, %v, want a zeroed pooledValue", pv, ok)
+		}
+		storage := Unbox(v)
+		*(*pooledValue)(storage) = pooledValue{ID: i + 1, Name: "used", Data: [4]uint64{1, 2, 3, 4}}
+		p.Put(v)
+		again := p.Get(typePointer)
+		if again.(pooledValue) != (pooledValue{}) {
+			t.Fatalf("Get after Put = %+v, not zeroed", again)
+		}
+		reused = Unbox(again) == storage
+	}
+	if !reused {
+		t.Error("Get never reused storage returned with Put")
+	}
+
+	other := TypePointerOf[[6]uint64]()
+	v := p.Get(typePointer)
+	storage := Unbox(v)
+	p.Put(v)
+	for i := 0; i < 20; i++ {
+		got := p.Get(other)
+		if _, ok := got.([6]uint64); !ok {
+			t.Fatalf("Get of [6]uint64 returned a %T", got)
+		}
+		if Unbox(got) == unsafe.Pointer(storage) {
+			t.Fatal("Get of one type reused storage of another")
+		}
+	}
+
+	x := 1
+	p.Put(&x)
+	p.Put(nil)
+	if got := p.Get(TypePointerOf[*int]()); got.(*int) != nil {
+		t.Error("Get of a direct-iface type did not return its zero value")
+	}
+}
@@ -0,0 +1,105 @@
+package unsafer
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+/*********************************************************************************
+	THE FOLLOWING FUNCTIONS AND TYPES ARE ADDED BY THE AUTHOR TO MAKE USE OF THE
+	TYPES IN unsafer.go IN NEW, INTERESTING, AND POSSIBLY UNSAFER WAYS, LICENSED
+	UNDER THE PERMISIVE BSD 2-CLAUSE LICENSE.
+*********************************************************************************/
+
+// A table mapping names to type pointers, for reconstructing values
+// from serialized type names with Invent.
+// The zero value is an empty registry ready to use, and it is safe for concurrent use.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+type TypeRegistry struct {
+	mu    sync.RWMutex
+	types map[string]uintptr
+}
+
+// Register typePointer under name, replacing any type pointer previously registered under it
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func (r *TypeRegistry) Register(name string, typePointer uintptr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.types == nil {
+		r.types = make(map[string]uintptr)
+	}
+	r.types[name] = typePointer
+}
+
+// Register the type pointer of the concrete type of v under its type name,
+// as returned by GetTypeName(v)
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func (r *TypeRegistry) RegisterValue(v any) {
+	r.Register(GetTypeName(v), GetTypePointer(v))
+}
+
+// Return the type pointer registered under name, and whether one was found
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func (r *TypeRegistry) Lookup(name string) (typePointer uintptr, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	typePointer, ok = r.types[name]
+	return typePointer, ok
+}
+
+var (
+	observingTypes    int32    // Non-zero while ObserveTypes is enabled, read atomically
+	observedTypes     sync.Map // Set of *TypeInternal already added to observedTypeNames
+	observedTypeNames sync.Map // Map from the FullTypeName of each observed type to the type pointer first observed with it
+)
+
+// Record t in the table searched by TypeByName, if it has not been already.
+// If another type was already recorded under the same name, it is kept.
+func observeType(t *TypeInternal) {
+	if t == nil {
+		return
+	}
+	if _, seen := observedTypes.Load(t); seen {
+		return
+	}
+	if _, seen := observedTypes.LoadOrStore(t, struct{}{}); !seen {
+		observedTypeNames.LoadOrStore(t.fullName(), uintptr(unsafe.Pointer(t)))
+	}
+}
+
+// Enable or disable recording of the types passed to GetTypePointer and GetTypeName
+// (including through TypeRegistry.RegisterValue), so that they can later be found with TypeByName.
+// Observation is disabled by default, as it adds a lookup to every such call.
+// Disabling it does not forget types already observed.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func ObserveTypes(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&observingTypes, v)
+}
+
+// Return the type pointer of the type with the given name, as returned by FullTypeName,
+// and whether one was found.
+//
+// Only types that have previously been observed by a call to GetTypePointer or GetTypeName
+// while ObserveTypes was enabled can be found.
+//
+// Distinct types can share a full name, such as two types with the same name declared in
+// different functions of one package. Only the first of them to be observed can be found.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func TypeByName(name string) (typePointer uintptr, ok bool) {
+	v, ok := observedTypeNames.Load(name)
+	if !ok {
+		return 0, false
+	}
+	return v.(uintptr), true
+}
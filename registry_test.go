@@ -0,0 +1,92 @@
+package unsafer
+
+import "testing"
+
+func TestTypeRegistry(t *testing.T) {
+	var r TypeRegistry
+	if _, ok := r.Lookup("int"); ok {
+		t.Fatal("empty TypeRegistry found a type")
+	}
+	values := []any{42, "str", paddedStruct{a: 1, b: 2, c: 3}, [2]uint16{1, 2}}
+	for _, v := range values {
+		r.RegisterValue(v)
+	}
+	r.Register("custom", TypePointerOf[float64]())
+	for _, v := range values {
+		typePointer, ok := r.Lookup(GetTypeName(v))
+		if !ok || typePointer != GetTypePointer(v) {
+			t.Errorf("Lookup(%q) = %#x, %v", GetTypeName(v), typePointer, ok)
+			continue
+		}
+		if got := Invent(Unbox(v), typePointer); got != v {
+			t.Errorf("Invent with looked-up type = %v, want %v", got, v)
+		}
+	}
+	if typePointer, ok := r.Lookup("custom"); !ok || typePointer != TypePointerOf[float64]() {
+		t.Error("Lookup of an explicitly registered name failed")
+	}
+
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for j := 0; j < 1000; j++ {
+				r.Lookup("int")
+			}
+		}()
+	}
+	r.Register("late", TypePointerOf[int8]())
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+}
+
+type observedOnce struct{ a int }
+
+type observedNever struct{ a int }
+
+// Observed types are never forgotten, so only the first run of TestTypeByName
+// (for example under -count) starts before observedOnce is observed
+var typeByNameRuns int
+
+func TestTypeByName(t *testing.T) {
+	ObserveTypes(true)
+	defer ObserveTypes(false)
+	typeByNameRuns++
+
+	name := FullTypeName(observedOnce{})
+	if _, ok := TypeByName(name); ok && typeByNameRuns == 1 {
+		t.Fatalf("TypeByName(%q) found a type before it was observed", name)
+	}
+	ptr := GetTypePointer(observedOnce{})
+	if got, ok := TypeByName(name); !ok || got != ptr {
+		t.Errorf("TypeByName(%q) = %#x, %v, want %#x, true", name, got, ok, ptr)
+	}
+	if name != "github.com/gabe-lee/unsafer.observedOnce" {
+		t.Errorf("FullTypeName(observedOnce{}) = %q", name)
+	}
+
+	firstPtr := func() uintptr {
+		type local int
+		return GetTypePointer(local(0))
+	}()
+	secondPtr := func() uintptr {
+		type local int
+		return GetTypePointer(local(0))
+	}()
+	if firstPtr == secondPtr {
+		t.Fatal("distinct local types share a type pointer")
+	}
+	if got, ok := TypeByName("github.com/gabe-lee/unsafer.local"); !ok || got != firstPtr {
+		t.Errorf("TypeByName(local) = %#x, %v, want the first observed %#x", got, ok, firstPtr)
+	}
+
+	ObserveTypes(false)
+	GetTypeName(observedNever{})
+	if _, ok := TypeByName(FullTypeName(observedNever{})); ok {
+		t.Error("TypeByName found a type observed while ObserveTypes was disabled")
+	}
+	if _, ok := TypeByName(name); !ok {
+		t.Error("disabling ObserveTypes forgot an observed type")
+	}
+}
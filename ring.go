@@ -0,0 +1,97 @@
+package unsafer
+
+import (
+	"unsafe"
+)
+
+/*********************************************************************************
+	THE FOLLOWING FUNCTIONS AND TYPES ARE ADDED BY THE AUTHOR TO MAKE USE OF THE
+	TYPES IN unsafer.go IN NEW, INTERESTING, AND POSSIBLY UNSAFER WAYS, LICENSED
+	UNDER THE PERMISIVE BSD 2-CLAUSE LICENSE.
+*********************************************************************************/
+
+// A fixed-capacity FIFO queue of values of a single type known only by type pointer,
+// stored inline in a single allocation rather than as individually boxed values.
+// A RawRing is NOT safe for concurrent use.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+type RawRing struct {
+	elemType *TypeInternal  // The type of the values held by the ring
+	data     unsafe.Pointer // Array of capacity values of elemType
+	capacity int            // Maximum number of values the ring can hold
+	head     int            // Index of the oldest value in the ring
+	count    int            // Number of values currently in the ring
+}
+
+// Return a new, empty RawRing able to hold capacity values of the type located at typePointer.
+// Use GetTypePointer(t any) to find type pointer addresses.
+//
+// Panics if capacity is not positive.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func NewRawRing(typePointer uintptr, capacity int) *RawRing {
+	if capacity <= 0 {
+		panic("unsafer: NewRawRing with non-positive capacity")
+	}
+	t := typeAt(typePointer)
+	return &RawRing{
+		elemType: t,
+		data:     unsafeNewArray(t, capacity),
+		capacity: capacity,
+	}
+}
+
+// Return a pointer to the slot at index i of the ring's backing array
+func (r *RawRing) slot(i int) unsafe.Pointer {
+	return unsafe.Add(r.data, uintptr(i)*r.elemType.Size)
+}
+
+// Number of values currently in the ring
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func (r *RawRing) Len() int {
+	return r.count
+}
+
+// Maximum number of values the ring can hold
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func (r *RawRing) Cap() int {
+	return r.capacity
+}
+
+// Copy the value held by v into the back of the ring.
+// Returns ErrTypeMismatch if v is not of the ring's type, and ErrRingFull if the ring is full.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func (r *RawRing) Push(v any) error {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if vv.Type != r.elemType {
+		return ErrTypeMismatch
+	}
+	if r.count == r.capacity {
+		return ErrRingFull
+	}
+	typedmemmove(r.elemType, r.slot((r.head+r.count)%r.capacity), vv.valuePointer())
+	r.count++
+	return nil
+}
+
+// Remove the value at the front of the ring, returning it and true,
+// or nil and false if the ring is empty.
+// The value is copied out of the ring, so it remains valid after later pushes.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func (r *RawRing) Pop() (value any, ok bool) {
+	if r.count == 0 {
+		return nil, false
+	}
+	slot := r.slot(r.head)
+	data := unsafeNew(r.elemType)
+	typedmemmove(r.elemType, data, slot)
+	// Clear the slot so the ring does not keep anything it pointed to alive
+	typedmemclr(r.elemType, slot)
+	r.head = (r.head + 1) % r.capacity
+	r.count--
+	return box(r.elemType, data), true
+}
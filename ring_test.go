@@ -0,0 +1,53 @@
+package unsafer
+
+import "testing"
+
+func TestRawRing(t *testing.T) {
+	r := NewRawRing(TypePointerOf[rawStruct](), 3)
+	if r.Len() != 0 || r.Cap() != 3 {
+		t.Fatalf("NewRawRing gave len %d, cap %d", r.Len(), r.Cap())
+	}
+	next, expect := 0, 0
+	push := func() {
+		t.Helper()
+		if err := r.Push(rawStruct{A: uint8(next), B: int32(-next), C: float64(next)}); err != nil {
+			t.Fatalf("Push(%d) returned %v", next, err)
+		}
+		next++
+	}
+	pop := func() {
+		t.Helper()
+		v, ok := r.Pop()
+		want := rawStruct{A: uint8(expect), B: int32(-expect), C: float64(expect)}
+		if !ok || v != want {
+			t.Fatalf("Pop = %v, %v, want %v", v, ok, want)
+		}
+		expect++
+	}
+	// Keep the ring partly full so that the head and tail wrap around repeatedly
+	push()
+	push()
+	for i := 0; i < 10; i++ {
+		push()
+		pop()
+		pop()
+		push()
+	}
+	if r.Len() != 2 {
+		t.Errorf("Len = %d after wrapping", r.Len())
+	}
+	push()
+	if err := r.Push(rawStruct{}); err != ErrRingFull {
+		t.Errorf("Push to a full ring returned %v", err)
+	}
+	for r.Len() > 0 {
+		pop()
+	}
+	if v, ok := r.Pop(); ok || v != nil {
+		t.Errorf("Pop of an empty ring = %v, %v", v, ok)
+	}
+	if err := r.Push(1); err != ErrTypeMismatch {
+		t.Errorf("Push of the wrong type returned %v", err)
+	}
+	expectPanic(t, "NewRawRing with capacity 0", func() { NewRawRing(TypePointerOf[int](), 0) })
+}
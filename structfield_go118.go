@@ -0,0 +1,17 @@
+//go:build !go1.19
+
+package unsafer
+
+// Return the byte offset of the field from the beginning of the struct
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func (f StructFieldInternal) Offset() uintptr {
+	return f.OffsetEmbed >> 1
+}
+
+// Whether the field is embedded in the struct
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func (f StructFieldInternal) Embedded() bool {
+	return f.OffsetEmbed&1 != 0
+}
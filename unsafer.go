@@ -1,6 +1,9 @@
 package unsafer
 
 import (
+	"errors"
+	"strconv"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -35,19 +38,22 @@ type SliceInternal struct {
 type TypeFlag uint8
 
 const (
-	TFlagUncommon      TypeFlag = 1 << 0 // ??
-	TFlagExtraStar     TypeFlag = 1 << 1 // Whether the Name field has an extra superfluous star in front of it
-	TFlagNamed         TypeFlag = 1 << 2 // Type has a defined name
-	TFlagRegularMemory TypeFlag = 1 << 3 // Whether the type can be treated in its entirety as contiguous block of Size bytes
+	TFlagUncommon       TypeFlag = 1 << 0 // ??
+	TFlagExtraStar      TypeFlag = 1 << 1 // Whether the Name field has an extra superfluous star in front of it
+	TFlagNamed          TypeFlag = 1 << 2 // Type has a defined name
+	TFlagRegularMemory  TypeFlag = 1 << 3 // Whether the type can be treated in its entirety as contiguous block of Size bytes
+	TFlagGCMaskOnDemand TypeFlag = 1 << 4 // Whether GCData is a **byte to a pointer bitmask built by the runtime on demand (newer Go versions)
+	TFlagDirectIface    TypeFlag = 1 << 5 // Whether the type is stored directly in an interface (newer Go versions, replacing KindDirectIface)
 )
 
-type NameOffset int32 // int32 offset from specific TypeInternal pointer to its string name
-type TypeOffset int32 // int32 offset from specific TypeInternal pointer to the type that is a POINTER-TO the type
+type NameOffset int32 // int32 offset from the type data section of the module containing a specific TypeInternal to its string name
+type TypeOffset int32 // int32 offset from the type data section of the module containing a specific TypeInternal to the type that is a POINTER-TO the type
 
 const (
 	NameExported                       byte = 1
 	NameFollowedByTagData              byte = 2
 	TagDataFollowedByPkgPathNameOffset byte = 4
+	NameEmbedded                       byte = 8 // Go 1.19+: the struct field with this name is embedded
 )
 
 // Encoded type name with additional data.
@@ -94,6 +100,9 @@ const (
 )
 
 // Internal structure of a map
+// on runtimes that implement maps as bucketed hash tables
+// (before Go 1.24, or Go 1.24 and 1.25 built with GOEXPERIMENT=noswissmap).
+// Other runtimes use SwissMapInternal instead.
 //
 // Unsafety Rating: ★★☆☆☆ (use caution)
 type MapInternal struct {
@@ -150,6 +159,54 @@ type BucketInternal struct {
 	TopHash [BucketSize]uint8
 }
 
+// Internal structure of a map on runtimes that implement maps as Swiss tables
+// (the default since Go 1.24, unless built with GOEXPERIMENT=noswissmap).
+// Older runtimes use MapInternal instead.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+type SwissMapInternal struct {
+	Used              uint64         // Number of Key-Value pairs currently active
+	Seed              uintptr        // Seed for the hashing algorithm
+	Directory         unsafe.Pointer // The single group of a small map when DirectoryLen == 0, otherwise an array of DirectoryLen *SwissTableInternal
+	DirectoryLen      int            // Number of entries in Directory, or 0 for a small map
+	GlobalDepth       uint8          // Number of hash bits used to select a directory entry
+	GlobalShift       uint8          // Shift applied to a hash to select a directory entry
+	Writing           uint8          // Non-zero while a goroutine is writing to the map
+	TombstonePossible bool           // Whether the map may contain deleted slots
+	ClearSeq          uint64         // Count of clear operations, used to detect clears during iteration
+}
+
+// Internals of one table of a large Swiss-table map.
+// Several consecutive directory entries may share the same table:
+// Index is the first of them.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+type SwissTableInternal struct {
+	Used       uint16         // Number of Key-Value pairs in this table
+	Capacity   uint16         // Total number of slots in this table
+	GrowthLeft uint16         // Number of slots that can be filled before the table must grow
+	LocalDepth uint8          // Number of hash bits this table is selected by
+	Index      int            // Index of the first directory entry that refers to this table
+	Groups     unsafe.Pointer // Array of GroupsMask+1 groups
+	GroupsMask uint64         // Number of groups minus one (the number of groups is a power of two)
+}
+
+// How many Key/Value slots a Swiss-table group holds.
+// Each group starts with an 8-byte control word, one control byte per slot,
+// followed by the slots themselves (see MapTypeInternal).
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+const SwissGroupSlots = 8
+
+const (
+	SwissCtrlEmpty   uint8 = 0x80 // Special control byte: This slot is empty
+	SwissCtrlDeleted uint8 = 0xFE // Special control byte: This slot held a pair that has since been deleted
+	// A control byte with its top bit clear marks a full slot, and holds the low 7 bits of its key's hash.
+)
+
+// Flags describing how a map type stores its keys and values
+type MapTypeFlag uint32
+
 // Internals of an interface that defines methods
 //
 // Unsafety Rating: ★★☆☆☆ (use caution)
@@ -197,6 +254,106 @@ type AnyInternal struct {
 	Data unsafe.Pointer // Pointer to the concrete data
 }
 
+// TypeInternal wrapper for slices
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+type SliceTypeInternal struct {
+	Type TypeInternal  // Basic type data for the slice
+	Elem *TypeInternal // The type of the slice's elements
+}
+
+// TypeInternal wrapper for arrays
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+type ArrayTypeInternal struct {
+	Type  TypeInternal  // Basic type data for the array
+	Elem  *TypeInternal // The type of the array's elements
+	Slice *TypeInternal // The type of a slice of the array's elements
+	Len   uintptr       // Length of the array
+}
+
+// TypeInternal wrapper for pointers
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+type PtrTypeInternal struct {
+	Type TypeInternal  // Basic type data for the pointer
+	Elem *TypeInternal // The type of the data pointed to
+}
+
+// Direction a channel type can be used in
+type ChanDir uintptr
+
+const (
+	ChanRecv ChanDir = 1                   // Channel can only be received from (<-chan T)
+	ChanSend ChanDir = 2                   // Channel can only be sent to (chan<- T)
+	ChanBoth ChanDir = ChanRecv | ChanSend // Channel can be sent to and received from (chan T)
+)
+
+// TypeInternal wrapper for channels
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+type ChanTypeInternal struct {
+	Type TypeInternal  // Basic type data for the channel
+	Elem *TypeInternal // The type of the channel's elements
+	Dir  ChanDir       // Direction the channel can be used in
+}
+
+// Bit set in FuncTypeInternal.OutCount when the function's final parameter is variadic
+const FuncVariadic uint16 = 1 << 15
+
+// TypeInternal wrapper for functions.
+// Immediately following the FuncTypeInternal (and an UncommonType, if the type has one) are
+// InCount parameter types then the return types, as *TypeInternal
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+type FuncTypeInternal struct {
+	Type     TypeInternal // Basic type data for the function
+	InCount  uint16       // Number of parameters
+	OutCount uint16       // Number of return values, with FuncVariadic set if the function is variadic
+}
+
+// Description of a single field of a struct type
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+type StructFieldInternal struct {
+	Name        EncodedName   // An EncodedName describing the name and tag data of the field
+	Type        *TypeInternal // The type of the field
+	OffsetEmbed uintptr       // Byte offset of the field. Before Go 1.19: shifted left by 1, with the lowest bit set if the field is embedded
+}
+
+// TypeInternal wrapper for structs
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+type StructTypeInternal struct {
+	Type    TypeInternal          // Basic type data for the struct
+	PkgPath EncodedName           // An EncodedName describing the package path of the struct
+	Fields  []StructFieldInternal // A list of the struct's fields, in declaration order
+}
+
+type TextOffset int32 // int32 offset from the text (code) section of the module containing a specific TypeInternal to a function
+
+// Additional type data for named types and types with methods, located immediately
+// after the kind-specific TypeInternal wrapper (such as StructTypeInternal) when TFlagUncommon is set
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+type UncommonTypeInternal struct {
+	PkgPath NameOffset // Offset to the EncodedName of the package path of the type
+	Mcount  uint16     // Number of methods
+	Xcount  uint16     // Number of exported methods, which are listed first
+	Moff    uint32     // Byte offset from the UncommonTypeInternal to its list of ConcreteMethod
+	_       uint32     // Unused
+}
+
+// Type describing a method on a concrete (non-interface) type
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+type ConcreteMethod struct {
+	Name          NameOffset // Offset pointing to the name of the method
+	Type          TypeOffset // Offset pointing to the type of the method, without a receiver
+	InterfaceFunc TextOffset // Offset pointing to the function used in interface calls
+	TypeFunc      TextOffset // Offset pointing to the function used in normal method calls
+}
+
 type Kind uint8
 
 const (
@@ -227,7 +384,7 @@ const (
 	KindStruct
 	KindUnsafePointer
 
-	KindDirectIface Kind = 1 << 5       // Whether the type is stored directly in an interface
+	KindDirectIface Kind = 1 << 5       // Whether the type is stored directly in an interface (older Go versions, see TFlagDirectIface)
 	KindGCProg      Kind = 1 << 6       // Whether the value pointed to by TypeInternal.GCData is a GCProgram
 	KindMask        Kind = (1 << 5) - 1 // Mask for base kinds without special flags
 )
@@ -238,12 +395,69 @@ const (
 // compiles down to zero instructions.
 //
 // Unsafety Rating: ★★★☆☆ (clearly unsafe)
+//
 //go:nosplit
 func NoEscape(p unsafe.Pointer) unsafe.Pointer {
 	x := uintptr(p)
 	return unsafe.Pointer(x ^ 0)
 }
 
+// Resolves a NameOffset relative to the module containing ptrInModule,
+// returning a pointer to the first byte of the EncodedName.
+// Implemented in the runtime package.
+//
+//go:linkname resolveNameOff reflect.resolveNameOff
+//go:noescape
+func resolveNameOff(ptrInModule unsafe.Pointer, off int32) unsafe.Pointer
+
+// Resolves a TypeOffset relative to the module containing rtype,
+// returning a pointer to the TypeInternal it describes.
+// Implemented in the runtime package.
+//
+//go:linkname resolveTypeOff reflect.resolveTypeOff
+//go:noescape
+func resolveTypeOff(rtype unsafe.Pointer, off int32) unsafe.Pointer
+
+// Computes the hash of the value of type t located at p, using seed h,
+// exactly as a map with hash seed h would. Panics if the type is not hashable.
+// Implemented in the runtime package.
+//
+//go:linkname typehash runtime.typehash
+//go:noescape
+func typehash(t *TypeInternal, p unsafe.Pointer, h uintptr) uintptr
+
+// Allocates zeroed memory for a single value of type t, visible to the garbage collector
+// as a value of that type. Implemented in the runtime package.
+//
+//go:linkname unsafeNew reflect.unsafe_New
+func unsafeNew(t *TypeInternal) unsafe.Pointer
+
+// Allocates zeroed memory for an array of n values of type t, visible to the garbage collector
+// as values of that type. Implemented in the runtime package.
+//
+//go:linkname unsafeNewArray reflect.unsafe_NewArray
+func unsafeNewArray(t *TypeInternal, n int) unsafe.Pointer
+
+// Copies a value of type t from src to dst, with the write barriers required
+// for types containing pointers. Implemented in the runtime package.
+//
+//go:linkname typedmemmove reflect.typedmemmove
+func typedmemmove(t *TypeInternal, dst, src unsafe.Pointer)
+
+// Zeroes the value of type t at ptr, with the write barriers required
+// for types containing pointers. Implemented in the runtime package.
+//
+//go:linkname typedmemclr reflect.typedmemclr
+//go:noescape
+func typedmemclr(t *TypeInternal, ptr unsafe.Pointer)
+
+// Finds or builds the method table for concrete type typ in interface inter, returning nil
+// if typ does not implement inter and canfail is true. inter MUST define at least one method.
+// Implemented in the runtime package.
+//
+//go:linkname getitab runtime.getitab
+func getitab(inter *ITypeInternal, typ *TypeInternal, canfail bool) *InterfaceDescription
+
 /*********************************************************************************
 	THE FOLLOWING FUNCTIONS AND TYPES ARE ADDED BY THE AUTHOR TO MAKE USE OF THE
 	ABOVE TYPES IN NEW, INTERESTING, AND POSSIBLY UNSAFER WAYS, LICENSED UNDER
@@ -253,10 +467,14 @@ func NoEscape(p unsafe.Pointer) unsafe.Pointer {
 // Return the unique type pointer of the supplied value.
 // This is THE definitive address where the type's definition resides,
 // and will not change for the duration of the program.
+// While ObserveTypes is enabled, the type is recorded so that it can later be found with TypeByName.
 //
 // Unsafety Rating: ★☆☆☆☆ (relatively safe)
 func GetTypePointer(t any) (pointer uintptr) {
 	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	if atomic.LoadInt32(&observingTypes) != 0 {
+		observeType(tt.Type)
+	}
 	return uintptr(unsafe.Pointer(tt.Type))
 }
 
@@ -317,3 +535,2028 @@ func Invent(data unsafe.Pointer, typePointer uintptr) (value any) {
 func ByteString(slice []byte) string {
 	return *(*string)(unsafe.Pointer(&slice))
 }
+
+// Return a byte slice that mirrors the data in str,
+// with both length and capacity matching the length of the string.
+//
+// Writing through the returned slice mutates the string's backing memory.
+// This is UNDEFINED BEHAVIOR if the string is a constant or literal residing
+// in read-only memory, and will most likely crash the program. Only write
+// through the slice when the string is known to be backed by writable memory
+// (for example, a string created with ByteString).
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func StringBytes(str string) []byte {
+	s := (*StringInternal)(unsafe.Pointer(&str))
+	slice := SliceInternal{
+		Data: s.Data,
+		Len:  s.Len,
+		Cap:  s.Len,
+	}
+	return slice.AsBytes()
+}
+
+// Return a pointer to the byte located offset bytes after the start of the encoded name
+func (n EncodedName) data(offset int) *byte {
+	return (*byte)(unsafe.Add(unsafe.Pointer(n.Bytes), offset))
+}
+
+// Read the varint located offset bytes after the start of the encoded name,
+// returning how many bytes the varint occupies and its decoded value
+func (n EncodedName) readVarint(offset int) (byteLen int, value int) {
+	for i := 0; ; i++ {
+		b := *n.data(offset + i)
+		value += int(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return i + 1, value
+		}
+	}
+}
+
+// Return the name described by the EncodedName.
+// The returned string aliases the encoded name data directly (no copy is made),
+// and will be empty if Bytes is nil.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func (n EncodedName) Name() string {
+	if n.Bytes == nil {
+		return ""
+	}
+	varintLen, nameLen := n.readVarint(1)
+	s := StringInternal{
+		Data: unsafe.Pointer(n.data(1 + varintLen)),
+		Len:  nameLen,
+	}
+	return s.AsString()
+}
+
+// Whether the NameExported flag is set on the EncodedName.
+// Returns false if Bytes is nil.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func (n EncodedName) IsExported() bool {
+	if n.Bytes == nil {
+		return false
+	}
+	return *n.Bytes&NameExported != 0
+}
+
+// Return the tag data that follows the name described by the EncodedName.
+// The returned string aliases the encoded name data directly (no copy is made).
+// ok will be false if Bytes is nil or the NameFollowedByTagData flag is not set.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func (n EncodedName) Tag() (tag string, ok bool) {
+	if n.Bytes == nil || *n.Bytes&NameFollowedByTagData == 0 {
+		return "", false
+	}
+	nameVarintLen, nameLen := n.readVarint(1)
+	tagOffset := 1 + nameVarintLen + nameLen
+	tagVarintLen, tagLen := n.readVarint(tagOffset)
+	s := StringInternal{
+		Data: unsafe.Pointer(n.data(tagOffset + tagVarintLen)),
+		Len:  tagLen,
+	}
+	return s.AsString(), true
+}
+
+// Return the EncodedName that a NameOffset resolves to for this type
+func (t *TypeInternal) nameOff(off NameOffset) EncodedName {
+	return EncodedName{Bytes: (*byte)(resolveNameOff(unsafe.Pointer(t), int32(off)))}
+}
+
+// Return the plain-text name of the type, as located by TypeInternal.Name.
+//
+// This is the full string form of the type as the runtime stores it, including
+// the package name for named types (e.g. "unsafer.Kind"), and may have a superfluous
+// leading star if TFlagExtraStar is set. The returned string aliases the type data
+// directly (no copy is made).
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func (t *TypeInternal) ResolvedName() string {
+	return t.nameOff(t.Name).Name()
+}
+
+// Return the plain-text name of the type, with the superfluous leading star
+// removed if TFlagExtraStar is set. Returns an empty string if t is nil.
+func (t *TypeInternal) nameWithoutExtraStar() string {
+	if t == nil {
+		return ""
+	}
+	name := t.ResolvedName()
+	if t.TypeFlags&TFlagExtraStar != 0 {
+		return name[1:]
+	}
+	return name
+}
+
+// Return the plain-text name of the concrete type of the supplied value,
+// with the superfluous leading star removed if TFlagExtraStar is set.
+// Returns an empty string if t is nil.
+// While ObserveTypes is enabled, the type is recorded so that it can later be found with TypeByName.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func GetTypeName(t any) string {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	if atomic.LoadInt32(&observingTypes) != 0 {
+		observeType(tt.Type)
+	}
+	return tt.Type.nameWithoutExtraStar()
+}
+
+// Return the TypeInternal that a TypeOffset resolves to for this type
+func (t *TypeInternal) typeOff(off TypeOffset) *TypeInternal {
+	return (*TypeInternal)(resolveTypeOff(unsafe.Pointer(t), int32(off)))
+}
+
+// Return the type that is a POINTER-TO this type (*T), as located by TypeInternal.Pointertype.
+// Returns nil if Pointertype is 0, which happens when the pointer type was never
+// compiled into the program.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func (t *TypeInternal) PointerTo() *TypeInternal {
+	if t.Pointertype == 0 {
+		return nil
+	}
+	return t.typeOff(t.Pointertype)
+}
+
+// Return the names of the methods declared by the interface type,
+// in the order they appear in MethodHeader (sorted by name).
+// The returned strings alias the type data directly (no copy is made).
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func (it *ITypeInternal) MethodNames() []string {
+	names := make([]string, len(it.MethodHeader))
+	for i, method := range it.MethodHeader {
+		names[i] = it.Type.nameOff(method.Name).Name()
+	}
+	return names
+}
+
+// Whether values of this type are stored directly in the Data word
+// of an interface, rather than Data being a pointer to the value.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func (t *TypeInternal) IsDirectIface() bool {
+	// Older runtimes mark this in the kind byte and newer ones in the type flags.
+	// Neither ever sets the other's bit, so checking both is correct for either.
+	return t.kind&KindDirectIface != 0 || t.TypeFlags&TFlagDirectIface != 0
+}
+
+// Whether the concrete type of the supplied value is stored directly in the Data word
+// of an interface (AnyInternal.Data IS the value), rather than Data being
+// a pointer to the value.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func IsDirectIface(t any) bool {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	return tt.Type.IsDirectIface()
+}
+
+// Get the size in bytes of the concrete type of the supplied value.
+// Does not include the size of any data POINTED TO by the value.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func GetSize(t any) uintptr {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	return tt.Type.Size
+}
+
+// Get the byte alignment of a variable of the concrete type of the supplied value
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func GetAlign(t any) uint8 {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	return tt.Type.Align
+}
+
+// Return a slice of To that mirrors the data in slice, with length and capacity
+// recomputed from the byte sizes of From and To. The Data pointer is preserved.
+//
+// Panics if the total byte length of slice is not an exact multiple
+// of the size of To, or if To has a size of zero.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func ReinterpretSlice[From any, To any](slice []From) []To {
+	var from From
+	var to To
+	fromSize, toSize := unsafe.Sizeof(from), unsafe.Sizeof(to)
+	if toSize == 0 {
+		panic("unsafer: ReinterpretSlice to zero-size element type")
+	}
+	s := (*SliceInternal)(unsafe.Pointer(&slice))
+	byteLen := uintptr(s.Len) * fromSize
+	if byteLen%toSize != 0 {
+		panic("unsafer: ReinterpretSlice byte length is not a multiple of destination element size")
+	}
+	result := SliceInternal{
+		Data: s.Data,
+		Len:  int(byteLen / toSize),
+		Cap:  int(uintptr(s.Cap) * fromSize / toSize),
+	}
+	return AsSlice[To](result)
+}
+
+// Return a slice of T whose data begins at data, with both length and capacity equal to length.
+//
+// Any changes to the memory in range data[0:length] will be reflected by the slice
+// and vice versa. An append call that reallocates the slice will break the relationship.
+// The caller is responsible for ensuring data points to at least length valid values of T
+// that remain alive for as long as the slice is in use.
+//
+// Panics if length is negative.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func SliceFromPointer[T any](data unsafe.Pointer, length int) []T {
+	if length < 0 {
+		panic("unsafer: SliceFromPointer with negative length")
+	}
+	s := SliceInternal{
+		Data: data,
+		Len:  length,
+		Cap:  length,
+	}
+	return AsSlice[T](s)
+}
+
+// Return a string whose data begins at data, with a length of length bytes.
+//
+// Any changes to the memory in range data[0:length] will be reflected by the string
+// in future reads. The caller is responsible for ensuring data points to at least
+// length valid bytes that remain alive for as long as the string is in use.
+//
+// Panics if length is negative.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func StringFromPointer(data unsafe.Pointer, length int) string {
+	if length < 0 {
+		panic("unsafer: StringFromPointer with negative length")
+	}
+	s := StringInternal{
+		Data: data,
+		Len:  length,
+	}
+	return s.AsString()
+}
+
+// Return a copy of the internal structure of the supplied slice
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func GetSliceInternal[T any](slice []T) SliceInternal {
+	return *(*SliceInternal)(unsafe.Pointer(&slice))
+}
+
+// Return a pointer to the internal structure of the slice pointed to by slice.
+// Any changes made through the returned pointer directly modify the slice.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func GetSliceInternalPointer[T any](slice *[]T) *SliceInternal {
+	return (*SliceInternal)(unsafe.Pointer(slice))
+}
+
+// Set the length of the slice pointed to by slice to n, without reslicing.
+// Exposes any previously unused capacity as readable elements.
+//
+// Panics if n is negative or greater than the capacity of the slice.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func SetSliceLen[T any](slice *[]T, n int) {
+	s := (*SliceInternal)(unsafe.Pointer(slice))
+	if n < 0 || n > s.Cap {
+		panic("unsafer: SetSliceLen out of range")
+	}
+	s.Len = n
+}
+
+// Set the capacity of the slice pointed to by slice to n, without reslicing.
+//
+// Setting the capacity beyond the size of the memory actually allocated for the slice
+// is UNDEFINED BEHAVIOR, as future appends will write into memory the slice does not own.
+//
+// Panics if n is negative or less than the length of the slice.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func SetSliceCap[T any](slice *[]T, n int) {
+	s := (*SliceInternal)(unsafe.Pointer(slice))
+	if n < 0 || n < s.Len {
+		panic("unsafer: SetSliceCap out of range")
+	}
+	s.Cap = n
+}
+
+// Return the internal structure of the map held by m, which may be nil for a nil map.
+// Panics if m does not hold a map, naming caller in the message.
+func getMapInternal(m any, caller string) *mapHeader {
+	mm := (*AnyInternal)(unsafe.Pointer(&m))
+	if mm.Type == nil || mm.Type.kind&KindMask != KindMap {
+		panic("unsafer: " + caller + " of non-map type")
+	}
+	return (*mapHeader)(mm.Data)
+}
+
+// Return the number of Key-Value pairs currently active in the map held by m,
+// analogous to len(m) for a map of known type.
+//
+// Panics if m does not hold a map.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func MapLen(m any) int {
+	mi := getMapInternal(m, "MapLen")
+	if mi == nil {
+		return 0
+	}
+	return mi.count()
+}
+
+// Return the flags describing special states of the map held by m.
+// Returns 0 for a nil map.
+//
+// This read is inherently racy: flags may change the instant after they are read,
+// and the read itself is not synchronized with other goroutines.
+// It is only suitable for best-effort debug assertions, such as detecting
+// that BeingWrittenTo is set while no write is expected.
+//
+// Panics if m does not hold a map.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func MapFlags(m any) MapFlag {
+	mi := getMapInternal(m, "MapFlags")
+	if mi == nil {
+		return 0
+	}
+	return mi.flags()
+}
+
+// Return the number of buckets in the map held by m, and the (approximate)
+// number of overflow buckets it has accumulated.
+// Returns 0, 0 for a nil map.
+//
+// On Swiss-table runtimes (see SwissMapInternal), buckets is the total number of groups
+// across all tables, and overflowApprox is always 0 since groups never overflow.
+//
+// Panics if m does not hold a map.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func MapBucketStats(m any) (buckets int, overflowApprox int) {
+	mi := getMapInternal(m, "MapBucketStats")
+	if mi == nil {
+		return 0, 0
+	}
+	return mi.bucketStats()
+}
+
+// Call fn with pointers to each key and value in the map held by m,
+// without allocating an iterator. Iteration stops early if fn returns false.
+//
+// Buckets are walked in memory order, including overflow chains and any old buckets
+// not yet evacuated during a grow. Cells whose TopHash marks them as empty or evacuated
+// are skipped. On Swiss-table runtimes (see SwissMapInternal), the groups of each table
+// are walked in memory order instead, skipping empty and deleted slots.
+//
+// The map MUST NOT be written to during iteration, including by fn.
+// The pointers passed to fn are only valid until the map is next written to.
+//
+// Panics if m does not hold a map.
+//
+// Unsafety Rating: ★★★★☆ (highly dangerous)
+func RangeMapRaw(m any, fn func(keyPtr, valuePtr unsafe.Pointer) bool) {
+	mi := getMapInternal(m, "RangeMapRaw")
+	mm := (*AnyInternal)(unsafe.Pointer(&m))
+	rangeMap((*MapTypeInternal)(unsafe.Pointer(mm.Type)), mi, fn)
+}
+
+// Classify a TopHash value from a BucketInternal.
+//
+// empty is true for LastEmptyCell, EmptyCell, and EvacuatedAndEmpty.
+// evacuated is true for EvacuatedToFirstHalf, EvacuatedToSecondHalf, and EvacuatedAndEmpty.
+// normal is true for any value >= MinimumTopHash, meaning the cell holds a valid,
+// non-evacuated Key/Value pair.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func ClassifyTopHash(h uint8) (empty bool, evacuated bool, normal bool) {
+	switch h {
+	case LastEmptyCell, EmptyCell:
+		return true, false, false
+	case EvacuatedToFirstHalf, EvacuatedToSecondHalf:
+		return false, true, false
+	case EvacuatedAndEmpty:
+		return true, true, false
+	}
+	return false, false, h >= MinimumTopHash
+}
+
+// Return a pointer to the concrete value held by the interface,
+// accounting for direct-iface types where Data IS the value
+func (a *AnyInternal) valuePointer() unsafe.Pointer {
+	if a.Type.IsDirectIface() {
+		return unsafe.Pointer(&a.Data)
+	}
+	return a.Data
+}
+
+// Whether a and b hold equal values of the same concrete type,
+// as determined by the runtime's type-specific comparator (TypeInternal.Equals).
+// Returns false if the concrete types differ, and true if both are nil.
+//
+// Panics if the concrete type is not comparable.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func Equal(a, b any) bool {
+	aa := (*AnyInternal)(unsafe.Pointer(&a))
+	bb := (*AnyInternal)(unsafe.Pointer(&b))
+	if aa.Type != bb.Type {
+		return false
+	}
+	if aa.Type == nil {
+		return true
+	}
+	if aa.Type.Equals == nil {
+		panic("unsafer: Equal on uncomparable type " + GetTypeName(a))
+	}
+	return aa.Type.Equals(aa.valuePointer(), bb.valuePointer())
+}
+
+// Return the hash of the value held by v, as computed by a map
+// with the supplied HashSeed (see MapInternal.HashSeed).
+// Two equal values of the same type will always produce the same hash for the same seed.
+// Returns seed unchanged if v is nil, as the runtime does for a nil interface key.
+//
+// Only the low 32 bits of the hash are returned. Maps also use its top bits,
+// so use TypeHasher to reproduce their full uintptr-sized hash.
+//
+// Panics if the concrete type of v is not hashable (for example, slices, maps, and funcs).
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func HashValue(v any, seed uint32) uint32 {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if vv.Type == nil {
+		return seed
+	}
+	return uint32(typehash(vv.Type, vv.valuePointer(), uintptr(seed)))
+}
+
+// Return the TypeInternal located at typePointer
+func typeAt(typePointer uintptr) *TypeInternal {
+	return *(**TypeInternal)(unsafe.Pointer(&typePointer))
+}
+
+// Build an 'any' value of type t from the value pointed to by p,
+// loading the value into Data for direct-iface types
+func box(t *TypeInternal, p unsafe.Pointer) (value any) {
+	a := (*AnyInternal)(unsafe.Pointer(&value))
+	a.Type = t
+	if t.IsDirectIface() {
+		a.Data = *(*unsafe.Pointer)(p)
+	} else {
+		a.Data = p
+	}
+	return value
+}
+
+// Invent an 'any' value of the type located at typePointer, using the
+// value pointed to by ptr. Unlike Invent, this correctly handles types that are
+// stored directly in the interface, so the result type-asserts cleanly.
+// Use GetTypePointer(t any) to find type pointer addresses.
+//
+// The type located at typePointer MUST have a size no larger than T,
+// and for indirect types the resulting 'any' aliases the memory at ptr.
+//
+// Unsafety Rating: ★★★★☆ (highly dangerous)
+func Cast[T any](ptr *T, typePointer uintptr) any {
+	return box(typeAt(typePointer), unsafe.Pointer(ptr))
+}
+
+// Return the unique type pointer of T without boxing a value of T into an 'any'.
+// Only a nil *T is boxed, which never allocates, and the element type is then
+// followed from the type of *T. Safe for concurrent use.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func TypePointerOf[T any]() uintptr {
+	return uintptr(unsafe.Pointer(typeOfGeneric[T]()))
+}
+
+// Get the basic kind of variable T embodies, without boxing a value of T into an 'any'
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func GetKindOf[T any]() Kind {
+	return typeOfGeneric[T]().kind & KindMask
+}
+
+// Return the type of T, followed from the type of a boxed nil *T
+func typeOfGeneric[T any]() *TypeInternal {
+	var t any = (*T)(nil)
+	return (*AnyInternal)(unsafe.Pointer(&t)).Type.elem()
+}
+
+// Whether the concrete type of the supplied value contains any pointers
+// the garbage collector must track. Values of types without pointers
+// can safely be treated as raw bytes.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func HasPointers(t any) bool {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	return tt.Type.PtrData != 0
+}
+
+// Whether the concrete type of the supplied value can be compared and copied
+// in its entirety as a contiguous block of Size bytes (TFlagRegularMemory)
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func IsRegularMemory(t any) bool {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	return tt.Type.TypeFlags&TFlagRegularMemory != 0
+}
+
+// Whether a and b hold equal values of the same concrete type.
+// When the type has TFlagRegularMemory set, the Size bytes of both values are
+// compared directly, otherwise this falls back to Equal.
+//
+// Panics if the concrete type is not comparable.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func RawEqual(a, b any) bool {
+	aa := (*AnyInternal)(unsafe.Pointer(&a))
+	bb := (*AnyInternal)(unsafe.Pointer(&b))
+	if aa.Type != bb.Type || aa.Type == nil || aa.Type.TypeFlags&TFlagRegularMemory == 0 {
+		return Equal(a, b)
+	}
+	size := int(aa.Type.Size)
+	return StringFromPointer(aa.valuePointer(), size) == StringFromPointer(bb.valuePointer(), size)
+}
+
+// Return a new 'any' holding a detached copy of the value held by v.
+// The Size bytes of the value are copied into freshly allocated memory,
+// so changes to the original value's memory are not reflected by the copy.
+//
+// This is a shallow copy: any pointers, slices, maps, or strings within the value
+// still alias the same data as the original.
+// Returns nil if v is nil.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func CloneBytes(v any) any {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if vv.Type == nil || vv.Type.IsDirectIface() {
+		// The value lives entirely in the Data word, which was copied when v was passed
+		return v
+	}
+	data := unsafeNew(vv.Type)
+	typedmemmove(vv.Type, data, vv.Data)
+	return box(vv.Type, data)
+}
+
+// Get the garbage collection metadata of the concrete type of the supplied value.
+// gcData is TypeInternal.GCData, and normally points to a plain pointer bitmap.
+//
+// isProgram reports that it instead points to a GC program (KindGCProg), which
+// only older Go versions generate.
+//
+// If the type has TFlagGCMaskOnDemand set (see GetTypeFlags), gcData is really a **byte:
+// newer Go versions use this for large types, storing a pointer to the bitmap there
+// once the runtime has built it. Until then the pointed-to *byte is nil.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func GCInfo(t any) (gcData *byte, isProgram bool) {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	return tt.Type.GCData, tt.Type.kind&KindGCProg != 0
+}
+
+// Get the byte alignment of a struct field of the concrete type of the supplied value
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func GetFieldAlign(t any) uint8 {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	return tt.Type.FieldAlign
+}
+
+// Whether a and b hold values of the exact same concrete type.
+// Returns true if both are nil.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func SameType(a, b any) bool {
+	aa := (*AnyInternal)(unsafe.Pointer(&a))
+	bb := (*AnyInternal)(unsafe.Pointer(&b))
+	return aa.Type == bb.Type
+}
+
+// Build an 'any' holding the value pointed to by ptr, choosing direct or indirect
+// storage based on whether T is stored directly in an interface.
+//
+// For types that are not direct-iface, the resulting 'any' aliases the memory at ptr
+// rather than holding a copy, so later changes to *ptr are reflected by the 'any'.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func Box[T any](ptr *T) any {
+	return box(typeAt(TypePointerOf[T]()), unsafe.Pointer(ptr))
+}
+
+// Return the Data pointer of the supplied value (AnyInternal.Data).
+// For types that are not direct-iface this points to the backing storage of the value,
+// for direct-iface types it IS the value.
+//
+// Only write through the returned pointer when v is known to own writable storage,
+// such as a value built with Box, CloneBytes, or ZeroOf. Values boxed from constants
+// or composite literals may reside in read-only memory, and writing to them will crash
+// the program. Zero values may be backed by a block of memory the runtime shares
+// between all of them, and writing to them silently corrupts every other zero value.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func Unbox(v any) unsafe.Pointer {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	return vv.Data
+}
+
+// Return a pointer to the backing storage of the supplied value as a *T,
+// avoiding the copy made by a type assertion.
+//
+// For types that are not direct-iface, changes made through the returned pointer are
+// reflected by v. For direct-iface types the value lives in the Data word itself,
+// so the returned pointer is to a copy of it.
+//
+// See Unbox for which values are safe to write to.
+//
+// Panics if the concrete type of v is not T.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func UnboxTyped[T any](v any) *T {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if uintptr(unsafe.Pointer(vv.Type)) != TypePointerOf[T]() {
+		panic("unsafer: UnboxTyped type mismatch: value is " + GetTypeName(v))
+	}
+	return (*T)(vv.valuePointer())
+}
+
+// NoEscapeValue hides the pointer to a value from escape analysis, so that passing
+// the returned pointer to a function that would otherwise force the value onto the heap
+// (for example, across an interface boundary) allows it to remain on the stack.
+//
+// The returned pointer MUST NOT be retained beyond the lifetime of the value it points to:
+// if the value stays on the stack and the pointer outlives the current function,
+// it will point into reused stack memory.
+//
+// Unsafety Rating: ★★★★☆ (highly dangerous)
+//
+//go:nosplit
+func NoEscapeValue[T any](p *T) *T {
+	return (*T)(NoEscape(unsafe.Pointer(p)))
+}
+
+// Return the string described by the StringInternal
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func (s StringInternal) AsString() string {
+	return *(*string)(unsafe.Pointer(&s))
+}
+
+// Return the byte slice described by the SliceInternal
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func (s SliceInternal) AsBytes() []byte {
+	return *(*[]byte)(unsafe.Pointer(&s))
+}
+
+// Return the slice of T described by the SliceInternal.
+// Len and Cap are interpreted as counts of T, not bytes.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func AsSlice[T any](s SliceInternal) []T {
+	return *(*[]T)(unsafe.Pointer(&s))
+}
+
+// Return the element type of container types (the value type for maps),
+// or nil if t is not a slice, array, pointer, channel, or map
+func (t *TypeInternal) elem() *TypeInternal {
+	switch t.kind & KindMask {
+	case KindSlice, KindArray:
+		// Elem is the first field following TypeInternal for both of these
+		return (*SliceTypeInternal)(unsafe.Pointer(t)).Elem
+	case KindChan:
+		return (*ChanTypeInternal)(unsafe.Pointer(t)).Elem
+	case KindPointer:
+		return (*PtrTypeInternal)(unsafe.Pointer(t)).Elem
+	case KindMap:
+		return (*MapTypeInternal)(unsafe.Pointer(t)).Elem
+	}
+	return nil
+}
+
+// Get the basic kind of the elements of the concrete type of the supplied value,
+// for slices, arrays, pointers, channels, and maps (the kind of the map's values).
+// ok is false for any other kind, or if t is nil.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func ElemKind(t any) (kind Kind, ok bool) {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	if tt.Type == nil {
+		return 0, false
+	}
+	elem := tt.Type.elem()
+	if elem == nil {
+		return 0, false
+	}
+	return elem.kind & KindMask, true
+}
+
+// Return the concrete type of the supplied value,
+// panicking if it is not of the given kind, naming caller in the message
+func typeOfKind(t any, kind Kind, caller string) *TypeInternal {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	if tt.Type == nil || tt.Type.kind&KindMask != kind {
+		panic("unsafer: " + caller + " of wrong kind: value is " + GetTypeName(t))
+	}
+	return tt.Type
+}
+
+// Return the concrete type of the supplied slice value as a SliceTypeInternal.
+//
+// Panics if t does not hold a slice.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func AsSliceType(t any) *SliceTypeInternal {
+	return (*SliceTypeInternal)(unsafe.Pointer(typeOfKind(t, KindSlice, "AsSliceType")))
+}
+
+// Return the concrete type of the supplied array value as an ArrayTypeInternal.
+//
+// Panics if t does not hold an array.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func AsArrayType(t any) *ArrayTypeInternal {
+	return (*ArrayTypeInternal)(unsafe.Pointer(typeOfKind(t, KindArray, "AsArrayType")))
+}
+
+// Return the concrete type of the supplied map value as a MapTypeInternal.
+//
+// Panics if m does not hold a map.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func AsMapType(m any) *MapTypeInternal {
+	return (*MapTypeInternal)(unsafe.Pointer(typeOfKind(m, KindMap, "AsMapType")))
+}
+
+// Return the offsets from the start of each bucket of the map held by m to its first key cell,
+// its first value cell, and its pointer to the next overflow bucket.
+// Unlike BucketDataStart, these account for the alignment of the map's key and value types,
+// so they address the cells correctly for any map (such as map[uint8]complex128).
+// Key and value cells are MapKeyValueSizes bytes apart.
+//
+// Panics if m does not hold a map, or on Swiss-table runtimes (see SwissMapInternal),
+// whose maps have no buckets.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func BucketLayout(m any) (keyStart, valueStart, overflowOffset uintptr) {
+	return AsMapType(m).bucketLayout()
+}
+
+// Return the size of the key and value cells in the buckets of the map held by m,
+// and whether those cells hold pointers to the keys and values (indirect)
+// rather than the keys and values themselves. Indirect cells are always
+// SystemPointerSize bytes, and are used when the key or value type is large.
+//
+// Panics if m does not hold a map.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func MapKeyValueSizes(m any) (keySize, valueSize uintptr, indirectKey, indirectValue bool) {
+	mt := AsMapType(m)
+	keySize, valueSize = mt.cellSizes()
+	return keySize, valueSize, mt.Flags&IndirectKey != 0, mt.Flags&IndirectElem != 0
+}
+
+// Return the concrete type of the supplied pointer value as a PtrTypeInternal.
+//
+// Panics if t does not hold a pointer.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func AsPtrType(t any) *PtrTypeInternal {
+	return (*PtrTypeInternal)(unsafe.Pointer(typeOfKind(t, KindPointer, "AsPtrType")))
+}
+
+// Return the concrete type of the supplied channel value as a ChanTypeInternal.
+//
+// Panics if c does not hold a channel.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func AsChanType(c any) *ChanTypeInternal {
+	return (*ChanTypeInternal)(unsafe.Pointer(typeOfKind(c, KindChan, "AsChanType")))
+}
+
+// Return the number of parameters and return values of the concrete type of the
+// supplied function value, and whether its final parameter is variadic.
+//
+// Panics if f does not hold a function.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func AsFuncType(f any) (in int, out int, variadic bool) {
+	ft := (*FuncTypeInternal)(unsafe.Pointer(typeOfKind(f, KindFunc, "AsFuncType")))
+	return int(ft.InCount), int(ft.OutCount &^ FuncVariadic), ft.OutCount&FuncVariadic != 0
+}
+
+// Return the descriptions of the fields of the concrete type of the supplied struct value,
+// in declaration order. The returned slice aliases the type data directly (no copy is made)
+// and MUST NOT be modified.
+//
+// Panics if v does not hold a struct.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func StructFields(v any) []StructFieldInternal {
+	st := (*StructTypeInternal)(unsafe.Pointer(typeOfKind(v, KindStruct, "StructFields")))
+	return st.Fields
+}
+
+// Find the field with the given name in the struct held by v, returning a pointer
+// to the field within the value's storage and the type of the field.
+// ok is false if the struct has no field with that name.
+//
+// Changes made through the returned pointer are reflected by v, unless the struct
+// is direct-iface, in which case the pointer is to a copy of the value.
+//
+// Panics if v does not hold a struct.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func FieldPointer(v any, name string) (fieldPtr unsafe.Pointer, fieldType *TypeInternal, ok bool) {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	for _, field := range StructFields(v) {
+		if field.Name.Name() == name {
+			return unsafe.Add(vv.valuePointer(), field.Offset()), field.Type, true
+		}
+	}
+	return nil, nil, false
+}
+
+var (
+	ErrNilTypePointer    = errors.New("unsafer: type pointer is nil")                         // A type pointer of 0 was supplied
+	ErrInvalidType       = errors.New("unsafer: type pointer does not point to a valid type") // The TypeInternal at a type pointer failed basic sanity checks
+	ErrNilData           = errors.New("unsafer: data pointer is nil")                         // A nil data pointer was supplied for a type that requires one
+	ErrTypeMismatch      = errors.New("unsafer: values are not of the same type")             // Two values were required to share a concrete type, but did not
+	ErrNotAddressable    = errors.New("unsafer: value is stored directly in its interface")   // A value must be written to, but it lives in an interface's Data word
+	ErrBufferTooShort    = errors.New("unsafer: buffer is too short")                         // A buffer holds fewer bytes than the value being read from it
+	ErrFieldNotFound     = errors.New("unsafer: struct has no field with that name")          // A struct field was looked up by a name the struct does not have
+	ErrRingFull          = errors.New("unsafer: ring buffer is full")                         // A value was pushed to a RawRing with no free space
+	ErrIncompatibleTypes = errors.New("unsafer: types differ in size or storage")             // Two types cannot stand in for one another in an interface
+)
+
+// Invent an 'any' value from the memory pointed to by data,
+// and the type located at typePointer, like Invent, but first verify that
+// typePointer is non-zero, the TypeInternal it points to looks sane
+// (a valid kind, power-of-two alignment, and a size that is a multiple of it),
+// and that data is non-nil for types that need it.
+//
+// These checks cannot PROVE typePointer is valid, and a pointer to arbitrary readable
+// memory may still pass them.
+//
+// Unsafety Rating: ★★★★☆ (highly dangerous)
+func InventChecked(data unsafe.Pointer, typePointer uintptr) (value any, err error) {
+	if typePointer == 0 {
+		return nil, ErrNilTypePointer
+	}
+	t := typeAt(typePointer)
+	kind := t.kind & KindMask
+	if kind < KindBool || kind > KindUnsafePointer || t.Align == 0 || t.Align&(t.Align-1) != 0 || t.Size%uintptr(t.Align) != 0 {
+		return nil, ErrInvalidType
+	}
+	if data == nil && t.Size != 0 && !t.IsDirectIface() {
+		return nil, ErrNilData
+	}
+	return Invent(data, typePointer), nil
+}
+
+// Copy the value held by src into the storage of the value held by dst,
+// so that dst reflects the contents of src.
+// Returns ErrTypeMismatch if dst and src do not share a concrete type,
+// and ErrNotAddressable if that type is direct-iface, as dst's value then lives
+// in its own Data word and cannot be written to.
+//
+// See Unbox for which values are safe to write to.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func CopyValue(dst, src any) error {
+	dd := (*AnyInternal)(unsafe.Pointer(&dst))
+	ss := (*AnyInternal)(unsafe.Pointer(&src))
+	if dd.Type != ss.Type || dd.Type == nil {
+		return ErrTypeMismatch
+	}
+	if dd.Type.IsDirectIface() {
+		return ErrNotAddressable
+	}
+	typedmemmove(dd.Type, dd.Data, ss.Data)
+	return nil
+}
+
+// Swap the values held by a and b in place, so each reflects the former contents of the other.
+// Returns ErrTypeMismatch if a and b do not share a concrete type,
+// and ErrNotAddressable if that type is direct-iface.
+//
+// Small pointer-free values are swapped through a stack buffer, while large values or values
+// containing pointers are swapped through heap scratch space so the garbage collector
+// always sees consistent pointers.
+//
+// See Unbox for which values are safe to write to.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func SwapValues(a, b any) error {
+	aa := (*AnyInternal)(unsafe.Pointer(&a))
+	bb := (*AnyInternal)(unsafe.Pointer(&b))
+	if aa.Type != bb.Type || aa.Type == nil {
+		return ErrTypeMismatch
+	}
+	t := aa.Type
+	if t.IsDirectIface() {
+		return ErrNotAddressable
+	}
+	if aa.Data == bb.Data {
+		return nil
+	}
+	const stackScratchSize = 64
+	if t.PtrData == 0 && t.Size <= stackScratchSize {
+		var scratch [stackScratchSize]byte
+		size := int(t.Size)
+		aBytes := SliceFromPointer[byte](aa.Data, size)
+		bBytes := SliceFromPointer[byte](bb.Data, size)
+		copy(scratch[:size], aBytes)
+		copy(aBytes, bBytes)
+		copy(bBytes, scratch[:size])
+		return nil
+	}
+	scratch := unsafeNew(t)
+	typedmemmove(t, scratch, aa.Data)
+	typedmemmove(t, aa.Data, bb.Data)
+	typedmemmove(t, bb.Data, scratch)
+	return nil
+}
+
+// Return an 'any' holding the zero value of the type located at typePointer,
+// backed by freshly allocated memory. Direct-iface types are boxed correctly.
+// Use GetTypePointer(t any) to find type pointer addresses.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func ZeroOf(typePointer uintptr) any {
+	t := typeAt(typePointer)
+	return box(t, unsafeNew(t))
+}
+
+// Append the raw Size bytes of the value held by v onto buf, returning the extended buffer.
+// Direct-iface values are read from their Data word. Returns buf unchanged if v is nil.
+//
+// The bytes include any padding and are in the platform's native byte order.
+// Types containing pointers (including strings, slices, and maps) serialize
+// meaningless addresses, and should not be appended.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func AppendRaw(buf []byte, v any) []byte {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if vv.Type == nil {
+		return buf
+	}
+	return append(buf, SliceFromPointer[byte](vv.valuePointer(), int(vv.Type.Size))...)
+}
+
+// Reconstruct a value of the type located at typePointer from the first Size bytes of buf,
+// as written by AppendRaw, returning the value and the remainder of buf.
+// The bytes are copied into freshly allocated storage, so value does not alias buf.
+// Returns ErrBufferTooShort if buf holds fewer than Size bytes.
+//
+// Reconstructing a type containing pointers produces pointers the garbage collector
+// knows nothing about, which is UNDEFINED BEHAVIOR unless they still point to live objects.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func ReadRaw(buf []byte, typePointer uintptr) (value any, rest []byte, err error) {
+	t := typeAt(typePointer)
+	if uintptr(len(buf)) < t.Size {
+		return nil, buf, ErrBufferTooShort
+	}
+	data := unsafeNew(t)
+	if t.Size != 0 {
+		typedmemmove(t, data, unsafe.Pointer(&buf[0]))
+	}
+	return box(t, data), buf[t.Size:], nil
+}
+
+// Return a string that mirrors the first n bytes of the data in slice.
+// The same aliasing rules as ByteString apply.
+//
+// Panics if n is negative or greater than the length of slice.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func ByteStringN(slice []byte, n int) string {
+	s := (*SliceInternal)(unsafe.Pointer(&slice))
+	if n < 0 || n > s.Len {
+		panic("unsafer: ByteStringN out of range")
+	}
+	str := StringInternal{
+		Data: s.Data,
+		Len:  n,
+	}
+	return str.AsString()
+}
+
+// Return a byte slice that mirrors the data in str, with a length matching
+// the length of the string and a capacity of 0.
+//
+// Because the capacity is 0, any append to the returned slice is forced to reallocate,
+// so it cannot write into the (possibly read-only) string data. Writing to the elements
+// of the slice directly is still UNDEFINED BEHAVIOR for strings in read-only memory,
+// so the slice should only be read from.
+//
+// As the length exceeds the capacity, reslicing the returned slice (other than to [:0])
+// will panic. Index into it, range over it, or copy from it instead.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func StringSliceReadOnly(str string) []byte {
+	s := (*StringInternal)(unsafe.Pointer(&str))
+	slice := SliceInternal{
+		Data: s.Data,
+		Len:  s.Len,
+		Cap:  0,
+	}
+	return slice.AsBytes()
+}
+
+// Return p advanced by the given number of bytes.
+// Equivalent to unsafe.Add, provided so that pointer arithmetic is never performed
+// on a uintptr the garbage collector cannot see.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+//
+//go:nosplit
+func Offset(p unsafe.Pointer, bytes uintptr) unsafe.Pointer {
+	return unsafe.Add(p, bytes)
+}
+
+// Return a pointer to the element at index i of an array of T beginning at base.
+//
+// No bounds checking is performed: the caller is responsible for ensuring that
+// index i lies within memory holding valid values of T.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func IndexPointer[T any](base unsafe.Pointer, i int) *T {
+	var t T
+	return (*T)(unsafe.Add(base, uintptr(i)*unsafe.Sizeof(t)))
+}
+
+// Whether v holds a nil value of a concrete nillable type (pointer, map, channel,
+// func, slice, or unsafe.Pointer). Such a v is not itself nil, as it carries a type,
+// so v != nil even though the value it holds is nil.
+// Returns false if v itself is nil, or holds a non-nillable type.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func IsTypedNil(v any) bool {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if vv.Type == nil {
+		return false
+	}
+	switch vv.Type.kind & KindMask {
+	case KindPointer, KindMap, KindChan, KindFunc, KindUnsafePointer, KindSlice:
+		// The pointer (or slice data pointer) is always the first word of the value
+		return *(*unsafe.Pointer)(vv.valuePointer()) == nil
+	}
+	return false
+}
+
+// Whether the concrete type of the supplied value is a signed or unsigned integer
+// (including uintptr)
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func IsInteger(t any) bool {
+	kind := GetKind(t)
+	return kind >= KindInt && kind <= KindUintptr
+}
+
+// Whether the concrete type of the supplied value is a floating point number
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func IsFloat(t any) bool {
+	kind := GetKind(t)
+	return kind == KindFloat32 || kind == KindFloat64
+}
+
+// Whether the concrete type of the supplied value is a complex number
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func IsComplex(t any) bool {
+	kind := GetKind(t)
+	return kind == KindComplex64 || kind == KindComplex128
+}
+
+// Whether the concrete type of the supplied value is an integer, floating point, or complex number
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func IsNumeric(t any) bool {
+	kind := GetKind(t)
+	return kind >= KindInt && kind <= KindComplex128
+}
+
+// Names of each base kind, indexed by Kind
+var kindNames = [...]string{
+	0:                 "invalid",
+	KindBool:          "bool",
+	KindInt:           "int",
+	KindInt8:          "int8",
+	KindInt16:         "int16",
+	KindInt32:         "int32",
+	KindInt64:         "int64",
+	KindUint:          "uint",
+	KindUint8:         "uint8",
+	KindUint16:        "uint16",
+	KindUint32:        "uint32",
+	KindUint64:        "uint64",
+	KindUintptr:       "uintptr",
+	KindFloat32:       "float32",
+	KindFloat64:       "float64",
+	KindComplex64:     "complex64",
+	KindComplex128:    "complex128",
+	KindArray:         "array",
+	KindChan:          "chan",
+	KindFunc:          "func",
+	KindInterface:     "interface",
+	KindMap:           "map",
+	KindPointer:       "ptr",
+	KindSlice:         "slice",
+	KindString:        "string",
+	KindStruct:        "struct",
+	KindUnsafePointer: "unsafe.Pointer",
+}
+
+// Return the name of the base kind (for example "int", "slice", or "map"),
+// followed by "|DirectIface" and/or "|GCProg" if those flags are set.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func (k Kind) String() string {
+	var name string
+	if base := k & KindMask; int(base) < len(kindNames) {
+		name = kindNames[base]
+	} else {
+		name = "kind" + strconv.Itoa(int(base))
+	}
+	if k&KindDirectIface != 0 {
+		name += "|DirectIface"
+	}
+	if k&KindGCProg != 0 {
+		name += "|GCProg"
+	}
+	return name
+}
+
+// Return the size in bytes of values of the base kind, for fixed-size primitive kinds
+// (bool, integers, uintptr, floats, complex numbers, and unsafe.Pointer).
+// int, uint, uintptr, and unsafe.Pointer are sized for the target architecture.
+// ok is false for all other kinds, whose size depends on the specific type.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func (k Kind) Size() (size uintptr, ok bool) {
+	switch k & KindMask {
+	case KindBool, KindInt8, KindUint8:
+		return 1, true
+	case KindInt16, KindUint16:
+		return 2, true
+	case KindInt32, KindUint32, KindFloat32:
+		return 4, true
+	case KindInt64, KindUint64, KindFloat64, KindComplex64:
+		return 8, true
+	case KindComplex128:
+		return 16, true
+	case KindInt, KindUint, KindUintptr, KindUnsafePointer:
+		return SystemPointerSize, true
+	}
+	return 0, false
+}
+
+// Function for hashing a value: (pointer to value, seed) -> hash,
+// compatible with the hash functions used by maps (see MapTypeInternal.Hasher)
+type Hasher func(ptr unsafe.Pointer, seed uintptr) uintptr
+
+// Return a Hasher for the concrete type of the supplied value that produces exactly the same
+// hash as a map keyed by that type would. ok is false if the type is not hashable
+// (for example, slices, maps, and funcs), in which case the Hasher is nil.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func TypeHasher(t any) (hasher Hasher, ok bool) {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	if tt.Type == nil || tt.Type.Equals == nil {
+		return nil, false
+	}
+	typ := tt.Type
+	return func(ptr unsafe.Pointer, seed uintptr) uintptr {
+		return typehash(typ, ptr, seed)
+	}, true
+}
+
+// Whether values of the concrete type of the supplied value can be compared with ==,
+// and therefore be used as map keys or with Equal.
+// Types containing interfaces are reported as comparable, though comparing them
+// may still panic if the values they hold are not.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func IsComparable(t any) bool {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	return tt.Type != nil && tt.Type.Equals != nil
+}
+
+// Return the value held by v as a T, and whether v actually held a T.
+// Equivalent to the assertion v.(T) for concrete (non-interface) types T,
+// implemented as a direct comparison of type pointers.
+// The type of T is read from the type of *T rather than from the TypePointerOf cache,
+// so the comparison costs no more than a pair of loads.
+// Returns the zero value of T and false on mismatch.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func AssertType[T any](v any) (value T, ok bool) {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if uintptr(unsafe.Pointer(vv.Type)) != TypePointerOf[T]() {
+		return value, false
+	}
+	return *(*T)(vv.valuePointer()), true
+}
+
+// Return the value held by v as a T WITHOUT checking that v actually holds a T,
+// correctly accounting for direct-iface storage.
+//
+// If v does not hold a T, this is UNDEFINED BEHAVIOR: the result is garbage at best,
+// and reading or writing through any pointers it contains can corrupt memory or crash
+// the program. Only use this where an invariant guarantees the type.
+//
+// Unsafety Rating: ★★★★★ (C U R S E D)
+func ForceAssert[T any](v any) T {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if typeAt(TypePointerOf[T]()).IsDirectIface() {
+		return *(*T)(unsafe.Pointer(&vv.Data))
+	}
+	return *(*T)(vv.Data)
+}
+
+// Return the bits of v reinterpreted as a To, like a bitcast.
+// Useful for converting between a float and its integer bit pattern,
+// or between types with identical memory layouts.
+//
+// Panics if From and To are not the same size.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func Reinterpret[From any, To any](v From) (out To) {
+	toType := typeAt(TypePointerOf[To]())
+	if typeAt(TypePointerOf[From]()).Size != toType.Size {
+		panic("unsafer: Reinterpret between types of different sizes")
+	}
+	// Copy into out rather than dereferencing &v as a *To, since From may be less aligned than To
+	typedmemmove(toType, unsafe.Pointer(&out), unsafe.Pointer(&v))
+	return out
+}
+
+// Return a pointer to the first element of the backing array of slice,
+// even if the slice is empty (in which case the pointer must not be dereferenced).
+// Returns nil for a nil slice.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func SliceData[T any](slice []T) *T {
+	return (*T)((*SliceInternal)(unsafe.Pointer(&slice)).Data)
+}
+
+// Return a slice of T whose backing array begins at data, with the given length and capacity.
+// The inverse of SliceData: the caller is responsible for ensuring data points to
+// at least capacity valid values of T.
+//
+// Panics if length is negative or capacity is less than length.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func MakeSlice[T any](data *T, length, capacity int) []T {
+	if length < 0 || capacity < length {
+		panic("unsafer: MakeSlice with invalid length or capacity")
+	}
+	s := SliceInternal{
+		Data: unsafe.Pointer(data),
+		Len:  length,
+		Cap:  capacity,
+	}
+	return AsSlice[T](s)
+}
+
+// Return an 'any' holding a slice that shares the Data, Len, and Cap of slice,
+// but whose type is the slice type located at newSliceTypePointer. This is Spoof for slices.
+// Use TypePointerOf[[]E]() or GetTypePointer(t any) to find type pointer addresses.
+//
+// The slice type must be supplied rather than just its element type,
+// as the runtime keeps no link from an element type to the type of a slice of it.
+//
+// Panics if newSliceTypePointer is not a slice type, or if its element type is not the same size as T.
+//
+// Unsafety Rating: ★★★★☆ (highly dangerous)
+func RetypeSlice[T any](slice []T, newSliceTypePointer uintptr) any {
+	sliceType := typeAt(newSliceTypePointer)
+	if sliceType.kind&KindMask != KindSlice {
+		panic("unsafer: RetypeSlice to non-slice type")
+	}
+	if (*SliceTypeInternal)(unsafe.Pointer(sliceType)).Elem.Size != typeAt(TypePointerOf[T]()).Size {
+		panic("unsafer: RetypeSlice to element type of a different size")
+	}
+	return Invent(unsafe.Pointer(&slice), newSliceTypePointer)
+}
+
+// Get the additional type data flags of the concrete type of the supplied value
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func GetTypeFlags(t any) TypeFlag {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	return tt.Type.TypeFlags
+}
+
+// Whether the concrete type of the supplied value has a defined name (TFlagNamed)
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func IsNamed(t any) bool {
+	return GetTypeFlags(t)&TFlagNamed != 0
+}
+
+// Whether the concrete type of the supplied value carries uncommon type data (TFlagUncommon),
+// which holds its package path and method list. All named types carry this data,
+// including predeclared types such as int, as do unnamed types that have methods.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func HasUncommon(t any) bool {
+	return GetTypeFlags(t)&TFlagUncommon != 0
+}
+
+// Return the uncommon type data of the type, or nil if TFlagUncommon is not set
+func (t *TypeInternal) uncommon() *UncommonTypeInternal {
+	if t.TypeFlags&TFlagUncommon == 0 {
+		return nil
+	}
+	var size uintptr
+	switch t.kind & KindMask {
+	case KindStruct:
+		size = unsafe.Sizeof(StructTypeInternal{})
+	case KindPointer:
+		size = unsafe.Sizeof(PtrTypeInternal{})
+	case KindFunc:
+		size = unsafe.Sizeof(FuncTypeInternal{})
+	case KindSlice:
+		size = unsafe.Sizeof(SliceTypeInternal{})
+	case KindArray:
+		size = unsafe.Sizeof(ArrayTypeInternal{})
+	case KindChan:
+		size = unsafe.Sizeof(ChanTypeInternal{})
+	case KindMap:
+		size = unsafe.Sizeof(MapTypeInternal{})
+	case KindInterface:
+		size = unsafe.Sizeof(ITypeInternal{})
+	default:
+		size = unsafe.Sizeof(TypeInternal{})
+	}
+	return (*UncommonTypeInternal)(unsafe.Add(unsafe.Pointer(t), size))
+}
+
+// Return the methods of the type, described by its uncommon type data.
+// The returned slice aliases the type data directly (no copy is made).
+func (t *TypeInternal) methods() []ConcreteMethod {
+	u := t.uncommon()
+	if u == nil || u.Mcount == 0 {
+		return nil
+	}
+	return SliceFromPointer[ConcreteMethod](unsafe.Add(unsafe.Pointer(u), u.Moff), int(u.Mcount))
+}
+
+// Return the names of the exported methods of the concrete type of the supplied value,
+// sorted by name. Returns nil if the type has no methods.
+// The returned strings alias the type data directly (no copy is made).
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func ConcreteMethods(v any) []string {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if vv.Type == nil {
+		return nil
+	}
+	u := vv.Type.uncommon()
+	if u == nil || u.Xcount == 0 {
+		return nil
+	}
+	methods := vv.Type.methods()[:u.Xcount]
+	names := make([]string, len(methods))
+	for i, method := range methods {
+		names[i] = vv.Type.nameOff(method.Name).Name()
+	}
+	return names
+}
+
+// Return the package path of the concrete type of the supplied value
+// (for example "github.com/gabe-lee/unsafer"), or an empty string for
+// unnamed types and predeclared types such as int.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func GetPackagePath(t any) string {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	return tt.Type.pkgPath()
+}
+
+// Return the package path of the type, or an empty string for unnamed,
+// predeclared, and nil types
+func (t *TypeInternal) pkgPath() string {
+	if t == nil || t.TypeFlags&TFlagNamed == 0 {
+		return ""
+	}
+	u := t.uncommon()
+	if u == nil || u.PkgPath == 0 {
+		return ""
+	}
+	return t.nameOff(u.PkgPath).Name()
+}
+
+// Return the fully qualified name of the concrete type of the supplied value:
+// its package path and its name joined by a "." for named types
+// (for example "github.com/gabe-lee/unsafer.Kind"), or the same name as
+// GetTypeName for predeclared and unnamed types (for example "int" or "[]unsafer.Kind").
+// Returns an empty string if t is nil.
+//
+// This differs from GetTypeName (and reflect's Type.String) only in using the full
+// package path rather than the package name.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func FullTypeName(t any) string {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	return tt.Type.fullName()
+}
+
+// Return the fully qualified name of the type, as FullTypeName
+func (t *TypeInternal) fullName() string {
+	name := t.nameWithoutExtraStar()
+	pkgPath := t.pkgPath()
+	if pkgPath == "" {
+		return name
+	}
+	// Strip the package name, skipping any '.' within type arguments of generic types
+	i := len(name) - 1
+	for brackets := 0; i >= 0 && (name[i] != '.' || brackets != 0); i-- {
+		switch name[i] {
+		case ']':
+			brackets++
+		case '[':
+			brackets--
+		}
+	}
+	return pkgPath + "." + name[i+1:]
+}
+
+// Return a 64-bit fingerprint of the concrete type of the supplied value,
+// which will not change for the duration of the program.
+// The high 32 bits are the type's Hash, and the low 32 bits are the low bits of its
+// type pointer, which are unique among the types of a single module.
+// Returns 0 if t is nil.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func TypeFingerprint(t any) uint64 {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	if tt.Type == nil {
+		return 0
+	}
+	return uint64(tt.Type.Hash)<<32 | uint64(uint32(uintptr(unsafe.Pointer(tt.Type))))
+}
+
+// Copy a single value of the concrete type of t from src to dst.
+// Types containing pointers are copied with the write barriers the garbage collector
+// requires to keep the pointed-to data alive, while pointer-free types are copied
+// as plain bytes. The memory regions may overlap.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func CopyTyped(dst, src unsafe.Pointer, t any) {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	if tt.Type.PtrData != 0 {
+		typedmemmove(tt.Type, dst, src)
+		return
+	}
+	size := int(tt.Type.Size)
+	copy(SliceFromPointer[byte](dst, size), SliceFromPointer[byte](src, size))
+}
+
+// Return a pointer to the T located offset bytes into the storage of the value held by v,
+// such as a struct field located with unsafe.Offsetof.
+//
+// The same aliasing rules as FieldPointer apply.
+// Panics if a T at offset would extend past the end of the value.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func FieldAt[T any](v any, offset uintptr) *T {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	var t T
+	if vv.Type == nil || offset+unsafe.Sizeof(t) > vv.Type.Size {
+		panic("unsafer: FieldAt out of range")
+	}
+	return (*T)(unsafe.Add(vv.valuePointer(), offset))
+}
+
+// Overwrite the field with the given name in the struct held by structVal with the value
+// held by newVal, so that structVal reflects the change.
+// Returns ErrFieldNotFound if the struct has no field with that name,
+// ErrTypeMismatch if newVal is not of the field's type, and ErrNotAddressable if
+// the struct is direct-iface.
+//
+// See Unbox for which values are safe to write to.
+//
+// Panics if structVal does not hold a struct.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func SetField(structVal any, name string, newVal any) error {
+	fieldPtr, fieldType, ok := FieldPointer(structVal, name)
+	if !ok {
+		return ErrFieldNotFound
+	}
+	nn := (*AnyInternal)(unsafe.Pointer(&newVal))
+	if nn.Type != fieldType {
+		return ErrTypeMismatch
+	}
+	if (*AnyInternal)(unsafe.Pointer(&structVal)).Type.IsDirectIface() {
+		return ErrNotAddressable
+	}
+	typedmemmove(fieldType, fieldPtr, nn.valuePointer())
+	return nil
+}
+
+// Call fn with the name and value of each field of the struct held by structVal,
+// in declaration order, stopping early if fn returns false.
+//
+// Each value aliases the field's storage within structVal rather than holding a copy,
+// unless the field's type is direct-iface, so changes made through Unbox of a value
+// are reflected by structVal. See Unbox for which values are safe to write to.
+//
+// Panics if structVal does not hold a struct.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func RangeFields(structVal any, fn func(name string, value any) bool) {
+	ss := (*AnyInternal)(unsafe.Pointer(&structVal))
+	fields := StructFields(structVal)
+	base := ss.valuePointer()
+	for _, field := range fields {
+		if !fn(field.Name.Name(), box(field.Type, unsafe.Add(base, field.Offset()))) {
+			return
+		}
+	}
+}
+
+// Estimate the total number of bytes of memory owned by the value held by v:
+// its own Size, plus everything transitively reachable through the pointers, strings,
+// slices (their full capacity), maps, and interfaces it contains.
+// Memory reachable through more than one path is only counted once.
+// Returns 0 if v is nil.
+//
+// This is an approximation: the contents of channels and closures are not counted,
+// map overhead is estimated from its buckets or groups, and allocator size-class rounding is ignored.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func DeepSize(v any) uintptr {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if vv.Type == nil {
+		return 0
+	}
+	visited := make(map[unsafe.Pointer]struct{})
+	return vv.Type.Size + ownedSize(vv.Type, vv.valuePointer(), visited)
+}
+
+// Return the number of bytes transitively owned by the value of type t at p,
+// excluding the Size of the value itself, skipping memory already in visited
+func ownedSize(t *TypeInternal, p unsafe.Pointer, visited map[unsafe.Pointer]struct{}) uintptr {
+	if t.PtrData == 0 {
+		return 0
+	}
+	firstVisit := func(target unsafe.Pointer) bool {
+		if target == nil {
+			return false
+		}
+		if _, seen := visited[target]; seen {
+			return false
+		}
+		visited[target] = struct{}{}
+		return true
+	}
+	switch t.kind & KindMask {
+	case KindPointer:
+		target := *(*unsafe.Pointer)(p)
+		if !firstVisit(target) {
+			return 0
+		}
+		elem := (*PtrTypeInternal)(unsafe.Pointer(t)).Elem
+		return elem.Size + ownedSize(elem, target, visited)
+	case KindString:
+		s := (*StringInternal)(p)
+		if !firstVisit(s.Data) {
+			return 0
+		}
+		return uintptr(s.Len)
+	case KindSlice:
+		s := (*SliceInternal)(p)
+		if !firstVisit(s.Data) {
+			return 0
+		}
+		elem := (*SliceTypeInternal)(unsafe.Pointer(t)).Elem
+		size := uintptr(s.Cap) * elem.Size
+		for i := 0; i < s.Len; i++ {
+			size += ownedSize(elem, unsafe.Add(s.Data, uintptr(i)*elem.Size), visited)
+		}
+		return size
+	case KindArray:
+		at := (*ArrayTypeInternal)(unsafe.Pointer(t))
+		var size uintptr
+		for i := uintptr(0); i < at.Len; i++ {
+			size += ownedSize(at.Elem, unsafe.Add(p, i*at.Elem.Size), visited)
+		}
+		return size
+	case KindStruct:
+		var size uintptr
+		for _, field := range (*StructTypeInternal)(unsafe.Pointer(t)).Fields {
+			size += ownedSize(field.Type, unsafe.Add(p, field.Offset()), visited)
+		}
+		return size
+	case KindInterface:
+		var dynamic *TypeInternal
+		if len((*ITypeInternal)(unsafe.Pointer(t)).MethodHeader) == 0 {
+			dynamic = (*AnyInternal)(p).Type
+		} else if desc := (*InterfaceInternal)(p).IDescription; desc != nil {
+			dynamic = desc.Type
+		}
+		if dynamic == nil {
+			return 0
+		}
+		data := (*AnyInternal)(p).Data
+		if dynamic.IsDirectIface() {
+			return ownedSize(dynamic, unsafe.Pointer(&data), visited)
+		}
+		if !firstVisit(data) {
+			return 0
+		}
+		return dynamic.Size + ownedSize(dynamic, data, visited)
+	case KindMap:
+		target := *(*unsafe.Pointer)(p)
+		if !firstVisit(target) {
+			return 0
+		}
+		mt := (*MapTypeInternal)(unsafe.Pointer(t))
+		mi := (*mapHeader)(target)
+		size := mapStorageSize(mt, mi)
+		rangeMap(mt, mi, func(keyPtr, valuePtr unsafe.Pointer) bool {
+			if mt.Flags&IndirectKey != 0 {
+				size += mt.Key.Size
+			}
+			if mt.Flags&IndirectElem != 0 {
+				size += mt.Elem.Size
+			}
+			size += ownedSize(mt.Key, keyPtr, visited) + ownedSize(mt.Elem, valuePtr, visited)
+			return true
+		})
+		return size
+	}
+	return 0
+}
+
+// Whether two type pointers describe identical types.
+// Within a program, Go type identity is exactly pointer identity of TypeInternal:
+// every type has a single definitive TypeInternal (see GetTypePointer).
+// Two zero type pointers (from nil interfaces) are identical to each other,
+// and not identical to any real type.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func TypeIdentical(aPtr, bPtr uintptr) bool {
+	return aPtr == bPtr
+}
+
+// Whether the concrete types of a and b are identical, as TypeIdentical.
+// Two nil interfaces are identical to each other, and not identical to any non-nil value.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func TypeIdenticalValues(a, b any) bool {
+	aa := (*AnyInternal)(unsafe.Pointer(&a))
+	bb := (*AnyInternal)(unsafe.Pointer(&b))
+	return TypeIdentical(uintptr(unsafe.Pointer(aa.Type)), uintptr(unsafe.Pointer(bb.Type)))
+}
+
+// Whether the map held by m contains the key pointed to by keyPtr,
+// which must point to a value of the map's key type.
+// The key is hashed and looked up in its bucket (or probe sequence of groups, on Swiss-table
+// runtimes) exactly as an index expression would.
+//
+// The map MUST NOT be written to concurrently.
+//
+// Panics if m does not hold a map.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func MapContainsRaw(m any, keyPtr unsafe.Pointer) bool {
+	mi := getMapInternal(m, "MapContainsRaw")
+	mm := (*AnyInternal)(unsafe.Pointer(&m))
+	return mapLookup((*MapTypeInternal)(unsafe.Pointer(mm.Type)), mi, keyPtr) != nil
+}
+
+// Return a pointer to the value stored under the key pointed to by keyPtr in the map held by m,
+// and whether the key was present. keyPtr must point to a value of the map's key type.
+// If the key is not present, valuePtr is nil.
+//
+// The returned pointer aliases the map's own storage: it is only valid until the map is next
+// written to, since a write may grow the map and move its entries.
+//
+// Panics if m does not hold a map.
+//
+// Unsafety Rating: ★★★★☆ (highly dangerous)
+func MapGetRaw(m any, keyPtr unsafe.Pointer) (valuePtr unsafe.Pointer, ok bool) {
+	mi := getMapInternal(m, "MapGetRaw")
+	mm := (*AnyInternal)(unsafe.Pointer(&m))
+	valuePtr = mapLookup((*MapTypeInternal)(unsafe.Pointer(mm.Type)), mi, keyPtr)
+	return valuePtr, valuePtr != nil
+}
+
+// Return the value held by t1 with its type replaced by the concrete type of t2, like Spoof,
+// but only if the two types have the same Size and are either both direct-iface or both not,
+// so the value's storage is laid out the way the new type expects.
+// Returns ErrIncompatibleTypes otherwise, or if either t1 or t2 is nil.
+//
+// These checks only guarantee the new type covers the same bytes: it still
+// reads them with its own meaning, so pointers must line up between the two types.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func SpoofChecked(t1 any, t2 any) (any, error) {
+	a, b := (*AnyInternal)(unsafe.Pointer(&t1)).Type, (*AnyInternal)(unsafe.Pointer(&t2)).Type
+	if a == nil || b == nil || a.Size != b.Size || a.IsDirectIface() != b.IsDirectIface() {
+		return nil, ErrIncompatibleTypes
+	}
+	return Spoof(t1, t2), nil
+}
+
+// Return a pointer to a T overlaid on the start of the storage of the value held by v,
+// such as a header struct laid over a byte array, and whether the overlay fits.
+// ok is false if v is nil, if a T is larger than the value, or if the value's storage
+// is not sufficiently aligned for a T.
+//
+// The same aliasing rules as UnboxTyped apply. T SHOULD NOT contain pointers
+// unless the overlaid memory holds valid pointers at the same offsets.
+//
+// Unsafety Rating: ★★★★☆ (highly dangerous)
+func ViewAs[T any](v any) (view *T, ok bool) {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	var t T
+	if vv.Type == nil || unsafe.Sizeof(t) > vv.Type.Size {
+		return nil, false
+	}
+	p := vv.valuePointer()
+	if alignPadding(uintptr(p), unsafe.Alignof(t)) != 0 {
+		return nil, false
+	}
+	return (*T)(p), true
+}
+
+// Return the Size bytes of storage of the value held by v as a byte slice aliasing them,
+// for inspecting a value's memory directly, such as in a hexdump.
+// Returns nil if v is nil.
+//
+// The bytes include any padding within the value, and their layout (byte order,
+// pointer size, field alignment) is specific to the platform the program runs on.
+// The same aliasing rules as UnboxTyped apply.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func RawBytes(v any) []byte {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if vv.Type == nil {
+		return nil
+	}
+	return SliceFromPointer[byte](vv.valuePointer(), int(vv.Type.Size))
+}
+
+// Read the little-endian uint16 stored in the 2 bytes at p.
+// p need not be aligned. Unlike encoding/binary, no bounds are checked:
+// all 2 bytes at p MUST be readable.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+//
+//go:nosplit
+func ReadUint16LE(p unsafe.Pointer) uint16 {
+	b := (*[2]byte)(p)
+	return uint16(b[0]) | uint16(b[1])<<8
+}
+
+// Read the big-endian uint16 stored in the 2 bytes at p.
+// p need not be aligned, and all 2 bytes at p MUST be readable.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+//
+//go:nosplit
+func ReadUint16BE(p unsafe.Pointer) uint16 {
+	b := (*[2]byte)(p)
+	return uint16(b[1]) | uint16(b[0])<<8
+}
+
+// Read the little-endian uint32 stored in the 4 bytes at p.
+// p need not be aligned, and all 4 bytes at p MUST be readable.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+//
+//go:nosplit
+func ReadUint32LE(p unsafe.Pointer) uint32 {
+	b := (*[4]byte)(p)
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+// Read the big-endian uint32 stored in the 4 bytes at p.
+// p need not be aligned, and all 4 bytes at p MUST be readable.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+//
+//go:nosplit
+func ReadUint32BE(p unsafe.Pointer) uint32 {
+	b := (*[4]byte)(p)
+	return uint32(b[3]) | uint32(b[2])<<8 | uint32(b[1])<<16 | uint32(b[0])<<24
+}
+
+// Read the little-endian uint64 stored in the 8 bytes at p.
+// p need not be aligned, and all 8 bytes at p MUST be readable.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+//
+//go:nosplit
+func ReadUint64LE(p unsafe.Pointer) uint64 {
+	b := (*[8]byte)(p)
+	return uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 | uint64(b[4])<<32 | uint64(b[5])<<40 | uint64(b[6])<<48 | uint64(b[7])<<56
+}
+
+// Read the big-endian uint64 stored in the 8 bytes at p.
+// p need not be aligned, and all 8 bytes at p MUST be readable.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+//
+//go:nosplit
+func ReadUint64BE(p unsafe.Pointer) uint64 {
+	b := (*[8]byte)(p)
+	return uint64(b[7]) | uint64(b[6])<<8 | uint64(b[5])<<16 | uint64(b[4])<<24 | uint64(b[3])<<32 | uint64(b[2])<<40 | uint64(b[1])<<48 | uint64(b[0])<<56
+}
+
+// Return the address just past the TypeInternal of the concrete type of the supplied value,
+// where the kind-specific type data begins (such as SliceTypeInternal.Elem for slices).
+// Every overlay returned by the AsXxxType functions is a TypeInternal followed by
+// the data at this address. Returns nil if t is nil.
+//
+// What lies at the returned address depends entirely on the type's kind,
+// and for basic kinds it is the start of the UncommonTypeInternal (if any),
+// or unrelated memory.
+//
+// Unsafety Rating: ★★★★☆ (highly dangerous)
+func ExtraTypeData(t any) unsafe.Pointer {
+	tt := (*AnyInternal)(unsafe.Pointer(&t))
+	if tt.Type == nil {
+		return nil
+	}
+	return unsafe.Add(unsafe.Pointer(tt.Type), unsafe.Sizeof(TypeInternal{}))
+}
+
+// Whether the value held by v is stored inline in v's Data word (AnyInternal.Data IS the value),
+// rather than Data pointing to the value's storage elsewhere in memory.
+// Always agrees with IsDirectIface(v), and returns false if v is nil.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func StoredInline(v any) bool {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	return vv.Type != nil && vv.Type.IsDirectIface()
+}
+
+// Invent an 'any' value of the type located at typePointer whose Data word IS data,
+// for boxing a value of a direct-iface type (such as a pointer, map, chan, or func)
+// straight from its bit pattern, without taking its address.
+// Use GetTypePointer(t any) to find type pointer addresses.
+//
+// Only pointer-shaped types are direct-iface: integers and other non-pointer types
+// are never stored inline, and must be boxed with Invent or Cast instead.
+// data MUST be a valid value of the type, as the garbage collector will treat it as a pointer.
+//
+// Panics if the type located at typePointer is not direct-iface.
+//
+// Unsafety Rating: ★★★★★ (C U R S E D)
+func InventInline(data uintptr, typePointer uintptr) (value any) {
+	t := typeAt(typePointer)
+	if !t.IsDirectIface() {
+		panic("unsafer: InventInline of type that is not direct-iface: " + t.nameWithoutExtraStar())
+	}
+	a := (*AnyInternal)(unsafe.Pointer(&value))
+	a.Type = t
+	a.Data = *(*unsafe.Pointer)(unsafe.Pointer(&data))
+	return value
+}
+
+// Format the value held by v as a string without using reflect or fmt.
+// Booleans, integers, floats, complex numbers, and strings are formatted from the value's
+// storage the way fmt's %v verb would. Values of any other kind are described by their
+// type name and their Data word, such as "main.Point@0xc000012345": the address of
+// their storage, or for direct-iface types (like pointers) the value itself.
+// Returns "<nil>" if v is nil.
+//
+// Unsafety Rating: ★☆☆☆☆ (relatively safe)
+func SprintValue(v any) string {
+	vv := (*AnyInternal)(unsafe.Pointer(&v))
+	if vv.Type == nil {
+		return "<nil>"
+	}
+	p := vv.valuePointer()
+	switch vv.Type.kind & KindMask {
+	case KindBool:
+		return strconv.FormatBool(*(*bool)(p))
+	case KindInt:
+		return strconv.FormatInt(int64(*(*int)(p)), 10)
+	case KindInt8:
+		return strconv.FormatInt(int64(*(*int8)(p)), 10)
+	case KindInt16:
+		return strconv.FormatInt(int64(*(*int16)(p)), 10)
+	case KindInt32:
+		return strconv.FormatInt(int64(*(*int32)(p)), 10)
+	case KindInt64:
+		return strconv.FormatInt(*(*int64)(p), 10)
+	case KindUint:
+		return strconv.FormatUint(uint64(*(*uint)(p)), 10)
+	case KindUint8:
+		return strconv.FormatUint(uint64(*(*uint8)(p)), 10)
+	case KindUint16:
+		return strconv.FormatUint(uint64(*(*uint16)(p)), 10)
+	case KindUint32:
+		return strconv.FormatUint(uint64(*(*uint32)(p)), 10)
+	case KindUint64:
+		return strconv.FormatUint(*(*uint64)(p), 10)
+	case KindUintptr:
+		return strconv.FormatUint(uint64(*(*uintptr)(p)), 10)
+	case KindFloat32:
+		return strconv.FormatFloat(float64(*(*float32)(p)), 'g', -1, 32)
+	case KindFloat64:
+		return strconv.FormatFloat(*(*float64)(p), 'g', -1, 64)
+	case KindComplex64:
+		return strconv.FormatComplex(complex128(*(*complex64)(p)), 'g', -1, 64)
+	case KindComplex128:
+		return strconv.FormatComplex(*(*complex128)(p), 'g', -1, 128)
+	case KindString:
+		return *(*string)(p)
+	}
+	return vv.Type.nameWithoutExtraStar() + "@0x" + strconv.FormatUint(uint64(uintptr(vv.Data)), 16)
+}
+
+// Allocate zeroed storage for a single value of the type located at typePointer, such as
+// the payload of a node in an intrusive container, returning both a pointer to the storage
+// and an 'any' holding the value in it. Use GetTypePointer(t any) to find type pointer addresses.
+//
+// For types that are not direct-iface, node aliases the storage at dataPtr, so writes
+// through dataPtr are reflected by node. A direct-iface node holds a copy of its zero value
+// instead, and must be rebuilt with box-aware helpers such as Cast after dataPtr is written.
+// Use Arena.New instead to allocate many pointer-free payloads together.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func NewNode(typePointer uintptr) (dataPtr unsafe.Pointer, node any) {
+	t := typeAt(typePointer)
+	dataPtr = unsafeNew(t)
+	return dataPtr, box(t, dataPtr)
+}
+
+// Partition values by the type pointer of their concrete types, read directly from each
+// value's interface. Values keep their relative order within each group, and nil values
+// are grouped under type pointer 0.
+//
+// Unsafety Rating: ☆☆☆☆☆ (perfectly safe)
+func GroupByType(values []any) map[uintptr][]any {
+	groups := make(map[uintptr][]any)
+	for i := range values {
+		vv := (*AnyInternal)(unsafe.Pointer(&values[i]))
+		typePointer := uintptr(unsafe.Pointer(vv.Type))
+		groups[typePointer] = append(groups[typePointer], values[i])
+	}
+	return groups
+}
+
+// Return a copy of the concrete method pointers in the method table of a non-empty interface
+// variable, in the order of the interface type's MethodHeader.
+// Passing a non-empty interface as an 'any' discards its method table,
+// so iface must instead hold a POINTER to the interface variable, such as &w for an io.Writer w.
+// Returns nil if the pointer or the interface variable it points to is nil,
+// or if the interface defines no methods.
+//
+// Panics if iface does not hold a pointer to an interface.
+//
+// Unsafety Rating: ★★★☆☆ (clearly unsafe)
+func MethodPointers(iface any) []uintptr {
+	ii := (*AnyInternal)(unsafe.Pointer(&iface))
+	if ii.Type == nil || ii.Type.kind&KindMask != KindPointer || ii.Type.elem().kind&KindMask != KindInterface {
+		panic("unsafer: MethodPointers of type that is not a pointer to an interface")
+	}
+	count := len((*ITypeInternal)(unsafe.Pointer(ii.Type.elem())).MethodHeader)
+	if ii.Data == nil || count == 0 {
+		return nil
+	}
+	desc := (*InterfaceInternal)(ii.Data).IDescription
+	if desc == nil {
+		return nil
+	}
+	pointers := make([]uintptr, count)
+	copy(pointers, SliceFromPointer[uintptr](unsafe.Pointer(&desc.FunctionPointers[0]), count))
+	return pointers
+}
+
+// Whether the concrete type of value implements the interface type ifacePtr,
+// found or added to the runtime's cache of method tables exactly as a type assertion would.
+// Every type implements an interface with no methods. Returns false if value is nil.
+// The ITypeInternal of an interface type I is located at TypePointerOf[I]().
+//
+// ifacePtr MUST point to the type of an interface.
+//
+// Unsafety Rating: ★★☆☆☆ (use caution)
+func Implements(value any, ifacePtr *ITypeInternal) bool {
+	vv := (*AnyInternal)(unsafe.Pointer(&value))
+	if vv.Type == nil {
+		return false
+	}
+	if len(ifacePtr.MethodHeader) == 0 {
+		return true
+	}
+	desc := getitab(ifacePtr, vv.Type, true)
+	return desc != nil && desc.FunctionPointers[0] != 0
+}
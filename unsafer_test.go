@@ -0,0 +1,2183 @@
+package unsafer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestIsDirectIface(t *testing.T) {
+	x := 1
+	direct := []any{&x, map[int]int{}, make(chan int), func() {}, unsafe.Pointer(&x), struct{ p *int }{&x}, [1]*int{&x}}
+	for _, v := range direct {
+		if !IsDirectIface(v) {
+			t.Errorf("IsDirectIface(%T) = false, want true", v)
+		}
+	}
+	indirect := []any{x, "str", []int{1}, struct{ a, b, c, d int }{}, [2]*int{}}
+	for _, v := range indirect {
+		if IsDirectIface(v) {
+			t.Errorf("IsDirectIface(%T) = true, want false", v)
+		}
+	}
+}
+
+func TestMapTypeInternal(t *testing.T) {
+	mt := AsMapType(map[string]int{})
+	if mt.Key != typeAt(TypePointerOf[string]()) || mt.Elem != typeAt(TypePointerOf[int]()) {
+		t.Fatalf("AsMapType(map[string]int) Key/Elem = %p/%p, want string/int", mt.Key, mt.Elem)
+	}
+	if mt.Hasher == nil {
+		t.Fatal("AsMapType(map[string]int).Hasher is nil")
+	}
+}
+
+func TestMapRaw(t *testing.T) {
+	type big [200]byte
+	for _, n := range []int{0, 1, 5, 8, 9, 100, 5000} {
+		m := make(map[int]int)
+		mb := make(map[big]big)
+		for i := 0; i < n; i++ {
+			m[i] = i * 10
+			var k, v big
+			k[0], k[1], v[199] = byte(i), byte(i>>8), byte(i)
+			mb[k] = v
+		}
+		if got := MapLen(m); got != n {
+			t.Errorf("n=%d: MapLen = %d", n, got)
+		}
+		if got := MapFlags(m); got != 0 {
+			t.Errorf("n=%d: MapFlags = %d, want 0", n, got)
+		}
+		seen := 0
+		RangeMapRaw(m, func(keyPtr, valuePtr unsafe.Pointer) bool {
+			if m[*(*int)(keyPtr)] != *(*int)(valuePtr) {
+				t.Errorf("n=%d: RangeMapRaw pair %d=%d does not match map", n, *(*int)(keyPtr), *(*int)(valuePtr))
+			}
+			seen++
+			return true
+		})
+		if seen != n {
+			t.Errorf("n=%d: RangeMapRaw visited %d pairs", n, seen)
+		}
+		seen = 0
+		RangeMapRaw(mb, func(keyPtr, valuePtr unsafe.Pointer) bool {
+			if (*big)(keyPtr)[0] != (*big)(valuePtr)[199] {
+				t.Errorf("n=%d: RangeMapRaw indirect pair does not match", n)
+			}
+			seen++
+			return true
+		})
+		if seen != n {
+			t.Errorf("n=%d: RangeMapRaw of indirect map visited %d pairs", n, seen)
+		}
+		for i := -1; i <= n; i++ {
+			k := i
+			valuePtr, ok := MapGetRaw(m, unsafe.Pointer(&k))
+			want, wantOk := m[k]
+			if ok != wantOk || MapContainsRaw(m, unsafe.Pointer(&k)) != wantOk || (ok && *(*int)(valuePtr) != want) {
+				t.Errorf("n=%d: MapGetRaw(%d) = %v, want %d, %v", n, k, ok, want, wantOk)
+			}
+		}
+		if n > 0 {
+			var k big
+			k[0] = byte(n - 1)
+			k[1] = byte((n - 1) >> 8)
+			if valuePtr, ok := MapGetRaw(mb, unsafe.Pointer(&k)); !ok || (*big)(valuePtr)[199] != byte(n-1) {
+				t.Errorf("n=%d: MapGetRaw of indirect key failed", n)
+			}
+		}
+		if n > 0 {
+			if buckets, _ := MapBucketStats(m); buckets < 1 {
+				t.Errorf("n=%d: MapBucketStats buckets = %d", n, buckets)
+			}
+		}
+		if size := DeepSize(m); size < uintptr(n)*16 {
+			t.Errorf("n=%d: DeepSize = %d, too small", n, size)
+		}
+	}
+
+	m := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		m[i] = i
+	}
+	for i := 0; i < 1000; i += 2 {
+		delete(m, i)
+	}
+	seen := 0
+	RangeMapRaw(m, func(keyPtr, valuePtr unsafe.Pointer) bool {
+		if *(*int)(keyPtr)%2 == 0 {
+			t.Errorf("RangeMapRaw visited deleted key %d", *(*int)(keyPtr))
+		}
+		seen++
+		return seen < 100
+	})
+	if seen != 100 {
+		t.Errorf("RangeMapRaw did not stop early: visited %d pairs", seen)
+	}
+	for i := 0; i < 1000; i++ {
+		k := i
+		if MapContainsRaw(m, unsafe.Pointer(&k)) != (i%2 == 1) {
+			t.Errorf("MapContainsRaw(%d) after deletes = %v", i, i%2 == 0)
+		}
+	}
+}
+
+func TestMapKeyValueSizes(t *testing.T) {
+	keySize, valueSize, indirectKey, indirectValue := MapKeyValueSizes(map[string]int{})
+	if keySize != unsafe.Sizeof("") || valueSize != unsafe.Sizeof(0) || indirectKey || indirectValue {
+		t.Errorf("MapKeyValueSizes(map[string]int) = %d, %d, %v, %v", keySize, valueSize, indirectKey, indirectValue)
+	}
+	keySize, valueSize, indirectKey, indirectValue = MapKeyValueSizes(map[int][300]byte{})
+	if keySize != unsafe.Sizeof(0) || valueSize != SystemPointerSize || indirectKey || !indirectValue {
+		t.Errorf("MapKeyValueSizes(map[int][300]byte) = %d, %d, %v, %v", keySize, valueSize, indirectKey, indirectValue)
+	}
+}
+
+type embeddedInner struct{ X int }
+
+type fieldsStruct struct {
+	A byte
+	embeddedInner
+	B string `json:"b"`
+	c [3]uint16
+	*fieldsStruct
+	D float64
+}
+
+func TestStructFields(t *testing.T) {
+	fields := StructFields(fieldsStruct{})
+	rt := reflect.TypeOf(fieldsStruct{})
+	if len(fields) != rt.NumField() {
+		t.Fatalf("StructFields returned %d fields, want %d", len(fields), rt.NumField())
+	}
+	for i, field := range fields {
+		want := rt.Field(i)
+		tag, _ := field.Name.Tag()
+		if field.Name.Name() != want.Name || field.Offset() != want.Offset || field.Embedded() != want.Anonymous || tag != string(want.Tag) {
+			t.Errorf("field %d = %q offset %d embedded %v tag %q, want %q offset %d embedded %v tag %q",
+				i, field.Name.Name(), field.Offset(), field.Embedded(), tag, want.Name, want.Offset, want.Anonymous, want.Tag)
+		}
+		rtype := want.Type
+		if field.Type != (*TypeInternal)((*AnyInternal)(unsafe.Pointer(&rtype)).Data) {
+			t.Errorf("field %d type does not match reflect", i)
+		}
+	}
+
+	v := fieldsStruct{D: 2.5}
+	fieldPtr, fieldType, ok := FieldPointer(v, "D")
+	if !ok || *(*float64)(fieldPtr) != 2.5 || fieldType != typeAt(TypePointerOf[float64]()) {
+		t.Errorf("FieldPointer(D) = %v, %v", fieldPtr, ok)
+	}
+	if _, _, ok := FieldPointer(v, "missing"); ok {
+		t.Error("FieldPointer found a missing field")
+	}
+}
+
+type (
+	methodMap    map[string]int
+	methodSlice  []int
+	methodArray  [2]int
+	methodChan   chan int
+	methodFunc   func()
+	methodStruct struct{ a int }
+	methodInt    int
+)
+
+func (methodMap) M()    {}
+func (methodSlice) M()  {}
+func (methodArray) M()  {}
+func (methodChan) M()   {}
+func (methodFunc) M()   {}
+func (methodStruct) M() {}
+func (methodInt) M()    {}
+func (methodInt) N()    {}
+func (methodInt) m()    {}
+
+func TestConcreteMethods(t *testing.T) {
+	for _, v := range []any{methodMap(nil), methodSlice(nil), methodArray{}, methodChan(nil), methodFunc(nil), methodStruct{}} {
+		if got := ConcreteMethods(v); len(got) != 1 || got[0] != "M" {
+			t.Errorf("ConcreteMethods(%T) = %q, want [M]", v, got)
+		}
+	}
+	if got := ConcreteMethods(methodInt(0)); len(got) != 2 || got[0] != "M" || got[1] != "N" {
+		t.Errorf("ConcreteMethods(methodInt) = %q, want [M N]", got)
+	}
+	if got := ConcreteMethods(map[string]int(nil)); got != nil {
+		t.Errorf("ConcreteMethods(map[string]int) = %q, want nil", got)
+	}
+	if got := ConcreteMethods(nil); got != nil {
+		t.Errorf("ConcreteMethods(nil) = %q, want nil", got)
+	}
+}
+
+func TestGCInfo(t *testing.T) {
+	small := struct {
+		a *int
+		b int
+		c *int
+	}{}
+	gcData, isProgram := GCInfo(small)
+	if gcData == nil || isProgram || GetTypeFlags(small)&TFlagGCMaskOnDemand != 0 {
+		t.Fatalf("GCInfo(small struct) = %p, %v", gcData, isProgram)
+	}
+	if *gcData&0b111 != 0b101 {
+		t.Errorf("GCInfo(small struct) bitmap = %08b, want ...101", *gcData)
+	}
+	big := ZeroOf(TypePointerOf[[1 << 16]*int]())
+	if _, isProgram := GCInfo(big); !isProgram && GetTypeFlags(big)&TFlagGCMaskOnDemand == 0 {
+		t.Errorf("GCInfo([1<<16]*int) reports a precomputed bitmap")
+	}
+}
+
+func TestHashValue(t *testing.T) {
+	a, b := 1, 1
+	type pair struct {
+		s string
+		n int
+	}
+	equal := [][2]any{
+		{42, 42},
+		{"hello", string([]byte("hello"))},
+		{pair{"x", 1}, pair{string([]byte{'x'}), 1}},
+		{&a, &a},
+		{1.5, 1.5},
+	}
+	for _, values := range equal {
+		for _, seed := range []uint32{0, 1, 0xdeadbeef} {
+			if HashValue(values[0], seed) != HashValue(values[1], seed) {
+				t.Errorf("HashValue(%v) differs between equal values with seed %d", values[0], seed)
+			}
+		}
+	}
+	if HashValue(&a, 7) == HashValue(&b, 7) && HashValue(&a, 8) == HashValue(&b, 8) {
+		t.Error("HashValue hashes the pointee of a pointer rather than the pointer")
+	}
+	if HashValue(nil, 7) != 7 {
+		t.Errorf("HashValue(nil, 7) = %d, want 7", HashValue(nil, 7))
+	}
+}
+
+type retypedInt32 int32
+
+func TestRetypeSlice(t *testing.T) {
+	ints := []int32{1, -2, 3}
+	retyped, ok := RetypeSlice(ints, TypePointerOf[[]retypedInt32]()).([]retypedInt32)
+	if !ok {
+		t.Fatal("RetypeSlice result does not assert to []retypedInt32")
+	}
+	if len(retyped) != 3 || cap(retyped) != 3 || retyped[0] != 1 || retyped[1] != -2 || retyped[2] != 3 {
+		t.Fatalf("RetypeSlice = %v", retyped)
+	}
+	retyped[0] = 10
+	if ints[0] != 10 {
+		t.Error("RetypeSlice result does not share storage with the original slice")
+	}
+	for _, typePointer := range []uintptr{TypePointerOf[[]int64](), TypePointerOf[int32]()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RetypeSlice(%s) did not panic", typeAt(typePointer).nameWithoutExtraStar())
+				}
+			}()
+			RetypeSlice(ints, typePointer)
+		}()
+	}
+}
+
+func TestStringBytes(t *testing.T) {
+	s := string([]byte("round trip"))
+	b := StringBytes(s)
+	if len(b) != len(s) || cap(b) != len(s) {
+		t.Fatalf("StringBytes len/cap = %d/%d, want %d", len(b), cap(b), len(s))
+	}
+	if GetSliceInternal(b).Data != (*StringInternal)(unsafe.Pointer(&s)).Data {
+		t.Error("StringBytes does not alias the string data")
+	}
+	back := ByteString(b)
+	if back != s || (*StringInternal)(unsafe.Pointer(&back)).Data != GetSliceInternal(b).Data {
+		t.Errorf("ByteString(StringBytes(s)) = %q, or does not share its data", back)
+	}
+	if b := StringBytes(""); len(b) != 0 || cap(b) != 0 {
+		t.Errorf("StringBytes(\"\") len/cap = %d/%d", len(b), cap(b))
+	}
+}
+
+type longNames struct {
+	Fxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx int
+	lowercase                                                                                                                                    int
+}
+
+func TestEncodedNameName(t *testing.T) {
+	fields := StructFields(longNames{})
+	if got := fields[0].Name.Name(); got != "Fxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx" {
+		t.Errorf("Name() of a %d-byte name = %q", len("Fxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx"), got)
+	}
+	if !fields[0].Name.IsExported() || fields[1].Name.IsExported() {
+		t.Errorf("IsExported() = %v, %v, want true, false", fields[0].Name.IsExported(), fields[1].Name.IsExported())
+	}
+	if fields[1].Name.Name() != "lowercase" {
+		t.Errorf("Name() = %q, want lowercase", fields[1].Name.Name())
+	}
+	var empty EncodedName
+	if empty.Name() != "" || empty.IsExported() {
+		t.Error("zero EncodedName is not empty and unexported")
+	}
+}
+
+type taggedStruct struct {
+	A int `json:"a,omitempty" db:"col_a"`
+	B string
+	C bool `x:""`
+}
+
+func TestEncodedNameTag(t *testing.T) {
+	rt := reflect.TypeOf(taggedStruct{})
+	for i, field := range StructFields(taggedStruct{}) {
+		want := rt.Field(i).Tag
+		tag, ok := field.Name.Tag()
+		if ok != (want != "") || tag != string(want) {
+			t.Errorf("field %s Tag() = %q, %v, want %q", field.Name.Name(), tag, ok, want)
+		}
+	}
+}
+
+// Return the concrete type of v
+func typeOfValue(v any) *TypeInternal {
+	return (*AnyInternal)(unsafe.Pointer(&v)).Type
+}
+
+func TestResolvedName(t *testing.T) {
+	for _, v := range []any{0, "", 1.5, Kind(0), taggedStruct{}, []int(nil), map[string]Kind(nil), new(taggedStruct), struct{ a int }{}} {
+		rt := reflect.TypeOf(v)
+		typ := typeOfValue(v)
+		got := typ.ResolvedName()
+		if typ.TypeFlags&TFlagExtraStar != 0 {
+			got = got[1:]
+		}
+		if got != rt.String() {
+			t.Errorf("ResolvedName of %v = %q, want %q", rt, got, rt.String())
+		}
+		if name := rt.Name(); name != "" && got[len(got)-len(name):] != name {
+			t.Errorf("ResolvedName of %v = %q, does not end in %q", rt, got, name)
+		}
+	}
+}
+
+func TestGetTypeName(t *testing.T) {
+	cases := []struct {
+		v    any
+		want string
+	}{
+		{0, "int"},
+		{new(int), "*int"},
+		{Kind(0), "unsafer.Kind"},
+		{new(Kind), "*unsafer.Kind"},
+		{struct {
+			A int
+			b string
+		}{}, "struct { A int; b string }"},
+		{[]*taggedStruct(nil), "[]*unsafer.taggedStruct"},
+		{nil, ""},
+	}
+	for _, c := range cases {
+		if got := GetTypeName(c.v); got != c.want {
+			t.Errorf("GetTypeName(%T) = %q, want %q", c.v, got, c.want)
+		}
+	}
+	// Most types store their name with a superfluous leading star
+	typ := typeOfValue(Kind(0))
+	if typ.TypeFlags&TFlagExtraStar == 0 {
+		t.Skip("unsafer.Kind does not have TFlagExtraStar on this runtime")
+	}
+	if raw := typ.ResolvedName(); raw != "*unsafer.Kind" {
+		t.Errorf("ResolvedName of unsafer.Kind = %q, want the extra star", raw)
+	}
+}
+
+func TestPointerTo(t *testing.T) {
+	if got := typeOfValue(0).PointerTo(); got != typeOfValue(new(int)) {
+		t.Errorf("PointerTo(int) = %p, want %p", got, typeOfValue(new(int)))
+	}
+	if got := typeOfValue(taggedStruct{}).PointerTo(); got != typeOfValue(&taggedStruct{}) {
+		t.Errorf("PointerTo(taggedStruct) = %p, want %p", got, typeOfValue(&taggedStruct{}))
+	}
+}
+
+func TestMethodNames(t *testing.T) {
+	it := (*ITypeInternal)(unsafe.Pointer(typeAt(TypePointerOf[io.ReadWriter]())))
+	if got := it.MethodNames(); len(got) != 2 || got[0] != "Read" || got[1] != "Write" {
+		t.Errorf("MethodNames(io.ReadWriter) = %q, want [Read Write]", got)
+	}
+	it = (*ITypeInternal)(unsafe.Pointer(typeAt(TypePointerOf[any]())))
+	if got := it.MethodNames(); len(got) != 0 {
+		t.Errorf("MethodNames(any) = %q, want none", got)
+	}
+}
+
+type paddedStruct struct {
+	a byte
+	b int64
+	c byte
+}
+
+func TestGetSizeAlign(t *testing.T) {
+	cases := []struct {
+		v     any
+		size  uintptr
+		align uintptr
+	}{
+		{int8(0), unsafe.Sizeof(int8(0)), unsafe.Alignof(int8(0))},
+		{int64(0), unsafe.Sizeof(int64(0)), unsafe.Alignof(int64(0))},
+		{"", unsafe.Sizeof(""), unsafe.Alignof("")},
+		{[]int(nil), unsafe.Sizeof([]int(nil)), unsafe.Alignof([]int(nil))},
+		{paddedStruct{}, unsafe.Sizeof(paddedStruct{}), unsafe.Alignof(paddedStruct{})},
+		{[3]uint16{}, unsafe.Sizeof([3]uint16{}), unsafe.Alignof([3]uint16{})},
+		{struct{}{}, 0, 1},
+	}
+	for _, c := range cases {
+		if got := GetSize(c.v); got != c.size {
+			t.Errorf("GetSize(%T) = %d, want %d", c.v, got, c.size)
+		}
+		if got := GetAlign(c.v); uintptr(got) != c.align {
+			t.Errorf("GetAlign(%T) = %d, want %d", c.v, got, c.align)
+		}
+	}
+}
+
+// Fail the test unless fn panics
+func expectPanic(t *testing.T, name string, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s did not panic", name)
+		}
+	}()
+	fn()
+}
+
+func TestReinterpretSlice(t *testing.T) {
+	words := []uint32{0x04030201, 0x08070605}
+	bytes := ReinterpretSlice[uint32, byte](words)
+	if len(bytes) != 8 || cap(bytes) != 8 || &bytes[0] != (*byte)(unsafe.Pointer(&words[0])) {
+		t.Fatalf("ReinterpretSlice to bytes = len %d cap %d, or does not alias", len(bytes), cap(bytes))
+	}
+	if ReadUint32LE(unsafe.Pointer(&bytes[4])) != words[1] && ReadUint32BE(unsafe.Pointer(&bytes[4])) != words[1] {
+		t.Error("ReinterpretSlice bytes do not match the words")
+	}
+	back := ReinterpretSlice[byte, uint32](bytes)
+	if len(back) != 2 || back[0] != words[0] || back[1] != words[1] || &back[0] != &words[0] {
+		t.Errorf("ReinterpretSlice back to words = %v", back)
+	}
+	expectPanic(t, "ReinterpretSlice of 7 bytes to uint32", func() { ReinterpretSlice[byte, uint32](bytes[:7]) })
+	expectPanic(t, "ReinterpretSlice to struct{}", func() { ReinterpretSlice[byte, struct{}](bytes) })
+}
+
+func TestSliceFromPointer(t *testing.T) {
+	backing := [4]int{1, 2, 3, 4}
+	s := SliceFromPointer[int](unsafe.Pointer(&backing[1]), 2)
+	if len(s) != 2 || cap(s) != 2 || s[0] != 2 || s[1] != 3 {
+		t.Fatalf("SliceFromPointer = %v (cap %d)", s, cap(s))
+	}
+	backing[1] = 20
+	if s[0] != 20 {
+		t.Error("SliceFromPointer does not alias the backing memory")
+	}
+	s = append(s, 5)
+	s[0] = 200
+	if backing[1] != 20 || backing[3] != 4 {
+		t.Error("append beyond cap did not reallocate away from the backing memory")
+	}
+	if got := SliceFromPointer[int](nil, 0); len(got) != 0 {
+		t.Errorf("SliceFromPointer(nil, 0) = %v", got)
+	}
+	expectPanic(t, "SliceFromPointer with negative length", func() { SliceFromPointer[int](unsafe.Pointer(&backing[0]), -1) })
+}
+
+func TestStringFromPointer(t *testing.T) {
+	buf := []byte("hello world")
+	s := StringFromPointer(unsafe.Pointer(&buf[6]), 5)
+	if s != "world" {
+		t.Fatalf("StringFromPointer = %q, want world", s)
+	}
+	buf[6] = 'W'
+	if s != "World" {
+		t.Errorf("StringFromPointer = %q after mutating the buffer, want World", s)
+	}
+	expectPanic(t, "StringFromPointer with negative length", func() { StringFromPointer(unsafe.Pointer(&buf[0]), -1) })
+}
+
+func TestGetSliceInternal(t *testing.T) {
+	s := make([]int, 3, 10)
+	si := GetSliceInternal(s)
+	if si.Data != unsafe.Pointer(&s[0]) || si.Len != len(s) || si.Cap != cap(s) {
+		t.Errorf("GetSliceInternal = %+v, want Data %p Len 3 Cap 10", si, &s[0])
+	}
+	GetSliceInternalPointer(&s).Len = 5
+	if len(s) != 5 {
+		t.Errorf("writing Len through GetSliceInternalPointer gave len %d, want 5", len(s))
+	}
+}
+
+func TestSetSliceLenCap(t *testing.T) {
+	s := make([]int, 2, 8)
+	s[0], s[1] = 1, 2
+	SetSliceLen(&s, 8)
+	if len(s) != 8 || s[0] != 1 || s[1] != 2 || s[7] != 0 {
+		t.Errorf("SetSliceLen(8) = %v", s)
+	}
+	SetSliceLen(&s, 2)
+	SetSliceCap(&s, 2)
+	if cap(s) != 2 {
+		t.Fatalf("SetSliceCap(2) gave cap %d", cap(s))
+	}
+	grown := append(s, 3)
+	if &grown[0] == &s[0] {
+		t.Error("append after SetSliceCap did not reallocate")
+	}
+	expectPanic(t, "SetSliceLen beyond cap", func() { SetSliceLen(&s, 3) })
+	expectPanic(t, "SetSliceLen negative", func() { SetSliceLen(&s, -1) })
+	expectPanic(t, "SetSliceCap below len", func() { SetSliceCap(&s, 1) })
+}
+
+func TestMapLen(t *testing.T) {
+	ints := map[int]int{}
+	strs := map[string][]byte{}
+	structs := map[paddedStruct]*int{}
+	for i := 0; i < 50; i++ {
+		ints[i] = i
+		strs[string(rune('a'+i))] = nil
+		structs[paddedStruct{b: int64(i)}] = nil
+	}
+	for i := 0; i < 20; i++ {
+		delete(ints, i)
+		delete(strs, string(rune('a'+i)))
+	}
+	for _, m := range []any{ints, strs, structs, map[int]bool{}, map[int]bool(nil)} {
+		if got, want := MapLen(m), reflect.ValueOf(m).Len(); got != want {
+			t.Errorf("MapLen(%T) = %d, want %d", m, got, want)
+		}
+	}
+	expectPanic(t, "MapLen of a slice", func() { MapLen([]int{}) })
+}
+
+func TestMapBucketStats(t *testing.T) {
+	if buckets, overflow := MapBucketStats(map[int]int(nil)); buckets != 0 || overflow != 0 {
+		t.Errorf("MapBucketStats(nil map) = %d, %d", buckets, overflow)
+	}
+	m := make(map[int]int)
+	previous := 0
+	for i := 0; i < 10000; i++ {
+		m[i] = i
+		buckets, overflow := MapBucketStats(m)
+		if buckets < previous || buckets < 1 || overflow < 0 {
+			t.Fatalf("after %d inserts MapBucketStats = %d, %d, previously %d buckets", i+1, buckets, overflow, previous)
+		}
+		previous = buckets
+	}
+	// Buckets (or Swiss-table groups) hold at most 8 pairs each
+	if previous < 10000/8 {
+		t.Errorf("MapBucketStats of 10000 pairs = %d buckets, too few", previous)
+	}
+	if _, ok := any(&MapInternal{}).(*mapHeader); ok && previous&(previous-1) != 0 {
+		t.Errorf("MapBucketStats = %d buckets, want a power of two", previous)
+	}
+}
+
+func TestRangeMapRaw(t *testing.T) {
+	m := map[string][]int{"a": {1}, "bb": {2, 2}, "ccc": {3, 3, 3}}
+	visited := map[string]bool{}
+	RangeMapRaw(m, func(keyPtr, valuePtr unsafe.Pointer) bool {
+		key, value := *(*string)(keyPtr), *(*[]int)(valuePtr)
+		if len(value) != len(key) || visited[key] {
+			t.Errorf("RangeMapRaw visited %q = %v", key, value)
+		}
+		visited[key] = true
+		return true
+	})
+	if len(visited) != len(m) {
+		t.Errorf("RangeMapRaw visited %d of %d pairs", len(visited), len(m))
+	}
+	calls := 0
+	RangeMapRaw(m, func(keyPtr, valuePtr unsafe.Pointer) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("RangeMapRaw continued after fn returned false: %d calls", calls)
+	}
+	RangeMapRaw(map[int]int(nil), func(keyPtr, valuePtr unsafe.Pointer) bool {
+		t.Error("RangeMapRaw visited a pair of a nil map")
+		return true
+	})
+}
+
+func TestClassifyTopHash(t *testing.T) {
+	cases := []struct {
+		h                        uint8
+		empty, evacuated, normal bool
+	}{
+		{LastEmptyCell, true, false, false},
+		{EmptyCell, true, false, false},
+		{EvacuatedToFirstHalf, false, true, false},
+		{EvacuatedToSecondHalf, false, true, false},
+		{EvacuatedAndEmpty, true, true, false},
+		{MinimumTopHash, false, false, true},
+		{MinimumTopHash + 1, false, false, true},
+		{255, false, false, true},
+	}
+	for _, c := range cases {
+		empty, evacuated, normal := ClassifyTopHash(c.h)
+		if empty != c.empty || evacuated != c.evacuated || normal != c.normal {
+			t.Errorf("ClassifyTopHash(%d) = %v, %v, %v, want %v, %v, %v", c.h, empty, evacuated, normal, c.empty, c.evacuated, c.normal)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	x, y := 1, 1
+	cases := []struct {
+		a, b any
+		want bool
+	}{
+		{paddedStruct{a: 1, b: 2}, paddedStruct{a: 1, b: 2}, true},
+		{paddedStruct{a: 1, b: 2}, paddedStruct{a: 1, b: 3}, false},
+		{"abc", string([]byte("abc")), true},
+		{&x, &x, true},
+		{&x, &y, false},
+		{1, int64(1), false},
+		{nil, nil, true},
+		{nil, 0, false},
+		{any(io.Writer(nil)), nil, true},
+	}
+	for _, c := range cases {
+		if got := Equal(c.a, c.b); got != c.want {
+			t.Errorf("Equal(%#v, %#v) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+	expectPanic(t, "Equal of slices", func() { Equal([]int{}, []int{}) })
+}
+
+func TestCast(t *testing.T) {
+	x := 42
+	if got, ok := Cast(&x, TypePointerOf[int]()).(int); !ok || got != 42 {
+		t.Errorf("Cast(&int, int) = %v, %v, want 42", got, ok)
+	}
+	if got, ok := Cast(&x, TypePointerOf[Kind]()).(Kind); !ok || got != 42 {
+		t.Errorf("Cast(&int, Kind) = %v, %v, want 42", got, ok)
+	}
+	p := &x
+	if got, ok := Cast(&p, TypePointerOf[*int]()).(*int); !ok || got != p {
+		t.Errorf("Cast(&*int, *int) = %p, %v, want %p", got, ok, p)
+	}
+	s := paddedStruct{a: 1, b: 2, c: 3}
+	if got, ok := Cast(&s, TypePointerOf[paddedStruct]()).(paddedStruct); !ok || got != s {
+		t.Errorf("Cast(&paddedStruct, paddedStruct) = %v, %v", got, ok)
+	}
+}
+
+func TestTypePointerOf(t *testing.T) {
+	if TypePointerOf[int]() != GetTypePointer(0) {
+		t.Error("TypePointerOf[int] does not match GetTypePointer(0)")
+	}
+	if TypePointerOf[paddedStruct]() != GetTypePointer(paddedStruct{}) {
+		t.Error("TypePointerOf[paddedStruct] does not match GetTypePointer")
+	}
+	if TypePointerOf[*int]() != GetTypePointer(new(int)) {
+		t.Error("TypePointerOf[*int] does not match GetTypePointer")
+	}
+	if allocs := testing.AllocsPerRun(100, func() { TypePointerOf[paddedStruct]() }); allocs != 0 {
+		t.Errorf("TypePointerOf allocates %v times per call", allocs)
+	}
+}
+
+var benchTypePointer uintptr
+
+func BenchmarkTypePointerOf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchTypePointer = TypePointerOf[paddedStruct]()
+	}
+}
+
+func BenchmarkGetTypePointer(b *testing.B) {
+	b.ReportAllocs()
+	v := paddedStruct{a: 1}
+	for i := 0; i < b.N; i++ {
+		benchTypePointer = GetTypePointer(v)
+	}
+}
+
+func TestGetKindOf(t *testing.T) {
+	if GetKindOf[[]int]() != KindSlice || GetKindOf[[]int]() != GetKind([]int{}) {
+		t.Errorf("GetKindOf[[]int] = %v, want slice", GetKindOf[[]int]())
+	}
+	if GetKindOf[map[string]int]() != KindMap || GetKindOf[map[string]int]() != GetKind(map[string]int{}) {
+		t.Errorf("GetKindOf[map[string]int] = %v, want map", GetKindOf[map[string]int]())
+	}
+	if GetKindOf[*int]() != KindPointer || GetKindOf[io.Reader]() != KindInterface || GetKindOf[Kind]() != KindUint8 {
+		t.Error("GetKindOf of pointer, interface, or named type is wrong")
+	}
+}
+
+func TestHasPointers(t *testing.T) {
+	cases := []struct {
+		v    any
+		want bool
+	}{
+		{byte(0), false},
+		{struct{ p *int }{}, true},
+		{struct{ a, b int }{}, false},
+		{"", true},
+		{[]byte(nil), true},
+		{[4]uint64{}, false},
+	}
+	for _, c := range cases {
+		if got := HasPointers(c.v); got != c.want {
+			t.Errorf("HasPointers(%T) = %v, want %v", c.v, got, c.want)
+		}
+	}
+}
+
+func TestIsRegularMemory(t *testing.T) {
+	cases := []struct {
+		v    any
+		want bool
+	}{
+		{0, true},
+		{struct{ a, b int32 }{}, true},
+		{[4]uint16{}, true},
+		{paddedStruct{}, false},
+		{1.5, false},
+		{struct{ f float32 }{}, false},
+		{"", false},
+	}
+	for _, c := range cases {
+		if got := IsRegularMemory(c.v); got != c.want {
+			t.Errorf("IsRegularMemory(%T) = %v, want %v", c.v, got, c.want)
+		}
+	}
+}
+
+func TestRawEqual(t *testing.T) {
+	type regular struct{ a, b int32 }
+	pairs := [][2]any{
+		{regular{1, 2}, regular{1, 2}},
+		{regular{1, 2}, regular{1, 3}},
+		{paddedStruct{a: 1, c: 2}, paddedStruct{a: 1, c: 2}},
+		{paddedStruct{a: 1}, paddedStruct{a: 2}},
+		{[3]int{1, 2, 3}, [3]int{1, 2, 3}},
+		{1.5, 1.5},
+		{regular{}, 0},
+		{nil, nil},
+	}
+	for _, pair := range pairs {
+		if got, want := RawEqual(pair[0], pair[1]), Equal(pair[0], pair[1]); got != want {
+			t.Errorf("RawEqual(%v, %v) = %v, Equal = %v", pair[0], pair[1], got, want)
+		}
+	}
+}
+
+var benchEqual bool
+
+func BenchmarkRawEqual(b *testing.B) {
+	x, y := any([8]int{1, 2, 3, 4, 5, 6, 7, 8}), any([8]int{1, 2, 3, 4, 5, 6, 7, 8})
+	for i := 0; i < b.N; i++ {
+		benchEqual = RawEqual(x, y)
+	}
+}
+
+func BenchmarkEqual(b *testing.B) {
+	x, y := any([8]int{1, 2, 3, 4, 5, 6, 7, 8}), any([8]int{1, 2, 3, 4, 5, 6, 7, 8})
+	for i := 0; i < b.N; i++ {
+		benchEqual = Equal(x, y)
+	}
+}
+
+func TestCloneBytes(t *testing.T) {
+	s := paddedStruct{a: 1, b: 2, c: 3}
+	original := Box(&s)
+	clone := CloneBytes(original)
+	s.b = 20
+	if original.(paddedStruct).b != 20 {
+		t.Fatal("Box does not alias its pointer")
+	}
+	if got := clone.(paddedStruct); got != (paddedStruct{a: 1, b: 2, c: 3}) {
+		t.Errorf("CloneBytes = %v after mutating the original", got)
+	}
+
+	x := 1
+	p := &x
+	clonedPointer := CloneBytes(p)
+	if got, ok := clonedPointer.(*int); !ok || got != p {
+		t.Errorf("CloneBytes(*int) = %v, want the same pointer %p", clonedPointer, p)
+	}
+	if CloneBytes(nil) != nil {
+		t.Error("CloneBytes(nil) is not nil")
+	}
+}
+
+func TestGetFieldAlign(t *testing.T) {
+	var s struct {
+		a byte
+		b int64
+		c uint16
+		d complex128
+	}
+	for _, v := range []any{s.a, s.b, s.c, s.d, paddedStruct{}, ""} {
+		if GetFieldAlign(v) != GetAlign(v) {
+			t.Errorf("GetFieldAlign(%T) = %d, GetAlign = %d", v, GetFieldAlign(v), GetAlign(v))
+		}
+	}
+	if uintptr(GetFieldAlign(s.b)) != unsafe.Alignof(s.b) {
+		t.Errorf("GetFieldAlign(int64) = %d, field alignment %d", GetFieldAlign(s.b), unsafe.Alignof(s.b))
+	}
+	if uintptr(GetFieldAlign(s.d)) != unsafe.Alignof(s.d) {
+		t.Errorf("GetFieldAlign(complex128) = %d, field alignment %d", GetFieldAlign(s.d), unsafe.Alignof(s.d))
+	}
+}
+
+func TestSameType(t *testing.T) {
+	cases := []struct {
+		a, b any
+		want bool
+	}{
+		{1, 2, true},
+		{1, int32(1), false},
+		{paddedStruct{}, struct{ a, b int }{}, false},
+		{[2]int{}, [3]int{}, false},
+		{nil, nil, true},
+		{nil, 0, false},
+	}
+	for _, c := range cases {
+		if got := SameType(c.a, c.b); got != c.want {
+			t.Errorf("SameType(%T, %T) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestBox(t *testing.T) {
+	x := 7
+	if got, ok := Box(&x).(int); !ok || got != 7 {
+		t.Errorf("Box(&int) = %v, %v", got, ok)
+	}
+	p := &x
+	if got, ok := Box(&p).(*int); !ok || got != p {
+		t.Errorf("Box(&*int) = %v, %v, want %p", got, ok, p)
+	}
+	large := [16]int{1, 2, 3}
+	if got, ok := Box(&large).([16]int); !ok || got != large {
+		t.Errorf("Box(&[16]int) = %v, %v", got, ok)
+	}
+	m := map[int]int{1: 2}
+	if got, ok := Box(&m).(map[int]int); !ok || got[1] != 2 {
+		t.Errorf("Box(&map) = %v, %v", got, ok)
+	}
+}
+
+func TestUnbox(t *testing.T) {
+	s := paddedStruct{b: 1}
+	v := Box(&s)
+	if Unbox(v) != unsafe.Pointer(&s) {
+		t.Error("Unbox does not return the boxed storage")
+	}
+	UnboxTyped[paddedStruct](v).b = 2
+	if v.(paddedStruct).b != 2 {
+		t.Error("write through UnboxTyped is not reflected by the value")
+	}
+	x := 1
+	if *UnboxTyped[*int](&x) != &x {
+		t.Error("UnboxTyped of a direct-iface value does not hold the value")
+	}
+	expectPanic(t, "UnboxTyped with the wrong type", func() { UnboxTyped[int64](v) })
+}
+
+var (
+	benchByte byte
+	// Called through a variable, so escape analysis must assume its argument escapes
+	readFirstByte = func(v any) byte { return v.(*paddedStruct).a }
+)
+
+func TestNoEscapeValue(t *testing.T) {
+	with := testing.AllocsPerRun(100, func() {
+		s := paddedStruct{a: 1}
+		benchByte = readFirstByte(NoEscapeValue(&s))
+	})
+	without := testing.AllocsPerRun(100, func() {
+		s := paddedStruct{a: 1}
+		benchByte = readFirstByte(&s)
+	})
+	if with != 0 || without == 0 {
+		t.Errorf("allocations with NoEscapeValue = %v, without = %v", with, without)
+	}
+}
+
+func BenchmarkNoEscapeValue(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := paddedStruct{a: byte(i)}
+		benchByte = readFirstByte(NoEscapeValue(&s))
+	}
+}
+
+func BenchmarkWithoutNoEscapeValue(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := paddedStruct{a: byte(i)}
+		benchByte = readFirstByte(&s)
+	}
+}
+
+func TestInternalAccessors(t *testing.T) {
+	buf := []byte("accessor")
+	s := StringInternal{Data: unsafe.Pointer(&buf[0]), Len: len(buf)}
+	if got := s.AsString(); got != "accessor" {
+		t.Errorf("AsString = %q", got)
+	}
+	si := SliceInternal{Data: unsafe.Pointer(&buf[0]), Len: 3, Cap: len(buf)}
+	if got := si.AsBytes(); string(got) != "acc" || cap(got) != len(buf) || &got[0] != &buf[0] {
+		t.Errorf("AsBytes = %q (cap %d)", got, cap(got))
+	}
+	words := []uint16{1, 2, 3}
+	if got := AsSlice[uint16](GetSliceInternal(words)); len(got) != 3 || &got[0] != &words[0] {
+		t.Errorf("AsSlice = %v", got)
+	}
+}
+
+func TestElemKind(t *testing.T) {
+	cases := []struct {
+		v    any
+		want Kind
+	}{
+		{[]int{}, KindInt},
+		{[2]string{}, KindString},
+		{new(float64), KindFloat64},
+		{make(chan bool), KindBool},
+		{map[string]complex64{}, KindComplex64},
+		{[][]int{}, KindSlice},
+	}
+	for _, c := range cases {
+		if got, ok := ElemKind(c.v); !ok || got != c.want {
+			t.Errorf("ElemKind(%T) = %v, %v, want %v", c.v, got, ok, c.want)
+		}
+	}
+	for _, v := range []any{0, "", struct{}{}, func() {}, nil} {
+		if _, ok := ElemKind(v); ok {
+			t.Errorf("ElemKind(%T) reported an element kind", v)
+		}
+	}
+}
+
+func TestAsSliceType(t *testing.T) {
+	if AsSliceType(make([]int, 0)).Elem != typeAt(GetTypePointer(0)) {
+		t.Error("AsSliceType([]int).Elem is not int")
+	}
+	if AsSliceType([]paddedStruct(nil)).Elem != typeAt(TypePointerOf[paddedStruct]()) {
+		t.Error("AsSliceType([]paddedStruct).Elem is not paddedStruct")
+	}
+	expectPanic(t, "AsSliceType of an array", func() { AsSliceType([1]int{}) })
+}
+
+func TestAsArrayType(t *testing.T) {
+	at := AsArrayType([4]int{})
+	if at.Len != 4 || at.Elem != typeAt(GetTypePointer(0)) || at.Slice != typeAt(TypePointerOf[[]int]()) {
+		t.Errorf("AsArrayType([4]int) = Len %d, Elem %p, Slice %p", at.Len, at.Elem, at.Slice)
+	}
+	expectPanic(t, "AsArrayType of a slice", func() { AsArrayType([]int{}) })
+}
+
+func TestMapKeyValueSizesInline(t *testing.T) {
+	cases := []struct {
+		m                    any
+		keySize, valueSize   uintptr
+		indirectK, indirectV bool
+	}{
+		{map[[32]byte]int{}, 32, unsafe.Sizeof(0), false, false},
+		{map[int][64]byte{}, unsafe.Sizeof(0), 64, false, false},
+		{map[[129]byte][129]byte{}, SystemPointerSize, SystemPointerSize, true, true},
+	}
+	for _, c := range cases {
+		keySize, valueSize, indirectK, indirectV := MapKeyValueSizes(c.m)
+		if keySize != c.keySize || valueSize != c.valueSize || indirectK != c.indirectK || indirectV != c.indirectV {
+			t.Errorf("MapKeyValueSizes(%T) = %d, %d, %v, %v, want %d, %d, %v, %v", c.m,
+				keySize, valueSize, indirectK, indirectV, c.keySize, c.valueSize, c.indirectK, c.indirectV)
+		}
+	}
+	expectPanic(t, "MapKeyValueSizes of a slice", func() { MapKeyValueSizes([]int{}) })
+}
+
+func TestAsPtrType(t *testing.T) {
+	if AsPtrType(new(int)).Elem != typeAt(GetTypePointer(0)) {
+		t.Error("AsPtrType(*int).Elem is not int")
+	}
+	if AsPtrType(new(*paddedStruct)).Elem != typeAt(TypePointerOf[*paddedStruct]()) {
+		t.Error("AsPtrType(**paddedStruct).Elem is not *paddedStruct")
+	}
+	defer func() {
+		msg, _ := recover().(string)
+		if msg != "unsafer: AsPtrType of wrong kind: value is int" {
+			t.Errorf("AsPtrType(int) panicked with %q", msg)
+		}
+	}()
+	AsPtrType(0)
+}
+
+func TestAsChanType(t *testing.T) {
+	c := make(chan int)
+	cases := []struct {
+		c   any
+		dir ChanDir
+	}{
+		{c, ChanBoth},
+		{(<-chan int)(c), ChanRecv},
+		{(chan<- int)(c), ChanSend},
+	}
+	for _, tc := range cases {
+		ct := AsChanType(tc.c)
+		if ct.Elem != typeAt(GetTypePointer(0)) || ct.Dir != tc.dir {
+			t.Errorf("AsChanType(%T) = Elem %p, Dir %d, want int, %d", tc.c, ct.Elem, ct.Dir, tc.dir)
+		}
+	}
+	expectPanic(t, "AsChanType of a slice", func() { AsChanType([]int{}) })
+}
+
+func TestAsFuncType(t *testing.T) {
+	cases := []struct {
+		f        any
+		in, out  int
+		variadic bool
+	}{
+		{func(int) (string, error) { return "", nil }, 1, 2, false},
+		{func(string, ...int) {}, 2, 0, true},
+		{func() {}, 0, 0, false},
+		{TestAsFuncType, 1, 0, false},
+	}
+	for _, c := range cases {
+		in, out, variadic := AsFuncType(c.f)
+		if in != c.in || out != c.out || variadic != c.variadic {
+			t.Errorf("AsFuncType(%T) = %d, %d, %v, want %d, %d, %v", c.f, in, out, variadic, c.in, c.out, c.variadic)
+		}
+	}
+	expectPanic(t, "AsFuncType of an int", func() { AsFuncType(0) })
+}
+
+func TestFieldPointerWrite(t *testing.T) {
+	s := fieldsStruct{D: 1.5}
+	s.X = 3
+	v := Box(&s)
+	fieldPtr, fieldType, ok := FieldPointer(v, "B")
+	if !ok || fieldType != typeAt(TypePointerOf[string]()) {
+		t.Fatalf("FieldPointer(B) = %v, %v", fieldType, ok)
+	}
+	*(*string)(fieldPtr) = "written"
+	if s.B != "written" {
+		t.Errorf("write through FieldPointer gave B = %q", s.B)
+	}
+	fieldPtr, _, ok = FieldPointer(v, "embeddedInner")
+	if !ok || (*embeddedInner)(fieldPtr).X != 3 {
+		t.Error("FieldPointer did not find the embedded field")
+	}
+	expectPanic(t, "FieldPointer of an int", func() { FieldPointer(0, "B") })
+}
+
+func TestInventChecked(t *testing.T) {
+	x := 5
+	if v, err := InventChecked(unsafe.Pointer(&x), TypePointerOf[int]()); err != nil || v != 5 {
+		t.Errorf("InventChecked(int) = %v, %v", v, err)
+	}
+	if _, err := InventChecked(unsafe.Pointer(&x), 0); err != ErrNilTypePointer {
+		t.Errorf("InventChecked with a zero type pointer returned %v", err)
+	}
+	if _, err := InventChecked(nil, TypePointerOf[int]()); err != ErrNilData {
+		t.Errorf("InventChecked with nil data returned %v", err)
+	}
+	var bogus TypeInternal
+	if _, err := InventChecked(unsafe.Pointer(&x), uintptr(unsafe.Pointer(&bogus))); err != ErrInvalidType {
+		t.Errorf("InventChecked with a zeroed type returned %v", err)
+	}
+	if v, err := InventChecked(nil, TypePointerOf[struct{}]()); err != nil || v != (struct{}{}) {
+		t.Errorf("InventChecked(struct{}) with nil data = %v, %v", v, err)
+	}
+}
+
+type copyStruct struct {
+	A int
+	B string
+	C *int
+}
+
+func TestCopyValue(t *testing.T) {
+	n := 7
+	dst := copyStruct{A: 1, B: "dst"}
+	src := copyStruct{A: 2, B: "src", C: &n}
+	if err := CopyValue(Box(&dst), Box(&src)); err != nil {
+		t.Fatalf("CopyValue returned %v", err)
+	}
+	if dst != src {
+		t.Errorf("CopyValue gave dst = %+v, want %+v", dst, src)
+	}
+	if err := CopyValue(Box(&dst), 5); err != ErrTypeMismatch {
+		t.Errorf("CopyValue of mismatched types returned %v", err)
+	}
+	if err := CopyValue(nil, nil); err != ErrTypeMismatch {
+		t.Errorf("CopyValue of nil values returned %v", err)
+	}
+	p, q := &n, &n
+	if err := CopyValue(p, q); err != ErrNotAddressable {
+		t.Errorf("CopyValue of direct-iface values returned %v", err)
+	}
+}
+
+func TestSwapValues(t *testing.T) {
+	n, m := 1, 2
+	a := copyStruct{A: 1, B: "a", C: &n}
+	b := copyStruct{A: 2, B: "b", C: &m}
+	va, vb := Box(&a), Box(&b)
+	if err := SwapValues(va, vb); err != nil {
+		t.Fatalf("SwapValues returned %v", err)
+	}
+	if a.A != 2 || a.B != "b" || a.C != &m || b.A != 1 || b.B != "a" || b.C != &n {
+		t.Errorf("SwapValues gave a = %+v, b = %+v", a, b)
+	}
+	if !SameType(va, vb) || !SameType(va, copyStruct{}) {
+		t.Error("SwapValues changed the types of its arguments")
+	}
+	x, y := [100]byte{0: 1}, [100]byte{0: 2}
+	if err := SwapValues(Box(&x), Box(&y)); err != nil || x[0] != 2 || y[0] != 1 {
+		t.Errorf("SwapValues of large arrays = %v, %d, %d", err, x[0], y[0])
+	}
+	i, j := 3, 4
+	if err := SwapValues(Box(&i), Box(&j)); err != nil || i != 4 || j != 3 {
+		t.Errorf("SwapValues of ints = %v, %d, %d", err, i, j)
+	}
+	if err := SwapValues(Box(&i), Box(&a)); err != ErrTypeMismatch {
+		t.Errorf("SwapValues of mismatched types returned %v", err)
+	}
+}
+
+func TestZeroOf(t *testing.T) {
+	if v, ok := ZeroOf(TypePointerOf[int]()).(int); !ok || v != 0 {
+		t.Errorf("ZeroOf(int) = %v, %v", v, ok)
+	}
+	if v, ok := ZeroOf(TypePointerOf[copyStruct]()).(copyStruct); !ok || v != (copyStruct{}) {
+		t.Errorf("ZeroOf(copyStruct) = %+v, %v", v, ok)
+	}
+	if v, ok := ZeroOf(TypePointerOf[*int]()).(*int); !ok || v != nil {
+		t.Errorf("ZeroOf(*int) = %v, %v", v, ok)
+	}
+	zero := ZeroOf(TypePointerOf[int]())
+	*(*int)(Unbox(zero)) = 5
+	if ZeroOf(TypePointerOf[int]()) != 0 {
+		t.Error("ZeroOf returned shared storage")
+	}
+}
+
+func TestAppendRaw(t *testing.T) {
+	buf := AppendRaw([]byte{0xFF}, 123456789)
+	if len(buf) != 1+int(unsafe.Sizeof(0)) {
+		t.Fatalf("AppendRaw(int) gave %d bytes", len(buf))
+	}
+	if v := SliceFromPointer[int](unsafe.Pointer(&buf[1]), 1)[0]; v != 123456789 {
+		t.Errorf("AppendRaw(int) read back as %d", v)
+	}
+	x := 5
+	buf = AppendRaw(nil, &x)
+	if *(*unsafe.Pointer)(unsafe.Pointer(&buf[0])) != unsafe.Pointer(&x) {
+		t.Error("AppendRaw of a direct-iface pointer did not append its address")
+	}
+	if buf := AppendRaw([]byte{1}, nil); len(buf) != 1 {
+		t.Errorf("AppendRaw(nil) appended %d bytes", len(buf)-1)
+	}
+}
+
+type rawStruct struct {
+	A uint8
+	B int32
+	C float64
+}
+
+func TestReadRaw(t *testing.T) {
+	values := []any{int(-42), uint16(0xBEEF), float64(3.25), true, rawStruct{A: 1, B: -2, C: 0.5}, [3]int8{1, 2, 3}}
+	var buf []byte
+	for _, v := range values {
+		buf = AppendRaw(buf, v)
+	}
+	rest := buf
+	for _, want := range values {
+		var got any
+		var err error
+		got, rest, err = ReadRaw(rest, GetTypePointer(want))
+		if err != nil || got != want {
+			t.Errorf("ReadRaw = %v, %v, want %v", got, err, want)
+		}
+		if !RawEqual(got, want) {
+			t.Errorf("ReadRaw(%T) is not byte-identical", want)
+		}
+	}
+	if len(rest) != 0 {
+		t.Errorf("ReadRaw left %d bytes", len(rest))
+	}
+	if _, rest, err := ReadRaw([]byte{1, 2}, TypePointerOf[int64]()); err != ErrBufferTooShort || len(rest) != 2 {
+		t.Errorf("ReadRaw of a short buffer = %v, %d", err, len(rest))
+	}
+}
+
+func TestByteStringN(t *testing.T) {
+	slice := []byte("length-prefixed")
+	str := ByteStringN(slice, 6)
+	if str != string(slice[:6]) {
+		t.Errorf("ByteStringN gave %q", str)
+	}
+	if (*StringInternal)(unsafe.Pointer(&str)).Data != unsafe.Pointer(&slice[0]) {
+		t.Error("ByteStringN did not share the data pointer")
+	}
+	if ByteStringN(slice, 0) != "" || ByteStringN(slice, len(slice)) != string(slice) {
+		t.Error("ByteStringN at the bounds did not match")
+	}
+	expectPanic(t, "ByteStringN past the length", func() { ByteStringN(slice, len(slice)+1) })
+	expectPanic(t, "ByteStringN of a negative length", func() { ByteStringN(slice, -1) })
+}
+
+func TestStringSliceReadOnly(t *testing.T) {
+	str := "read only"
+	slice := StringSliceReadOnly(str)
+	if len(slice) != len(str) || cap(slice) != 0 {
+		t.Fatalf("StringSliceReadOnly gave len %d, cap %d", len(slice), cap(slice))
+	}
+	for i := range slice {
+		if slice[i] != str[i] {
+			t.Fatalf("StringSliceReadOnly byte %d = %q", i, slice[i])
+		}
+	}
+	strData := (*StringInternal)(unsafe.Pointer(&str)).Data
+	if (*SliceInternal)(unsafe.Pointer(&slice)).Data != strData {
+		t.Error("StringSliceReadOnly did not alias the string data")
+	}
+	appended := append(slice, '!')
+	if (*SliceInternal)(unsafe.Pointer(&appended)).Data == strData {
+		t.Error("append to StringSliceReadOnly did not reallocate")
+	}
+	if str != "read only" {
+		t.Errorf("append modified the string to %q", str)
+	}
+}
+
+func TestOffset(t *testing.T) {
+	s := []uint32{10, 20, 30, 40}
+	base := unsafe.Pointer(&s[0])
+	for i := range s {
+		if p := Offset(base, uintptr(i)*unsafe.Sizeof(s[0])); p != unsafe.Pointer(&s[i]) || *(*uint32)(p) != s[i] {
+			t.Errorf("Offset to element %d = %p, want %p", i, p, &s[i])
+		}
+	}
+}
+
+func TestIndexPointer(t *testing.T) {
+	s := []rawStruct{{A: 1}, {A: 2}, {A: 3}}
+	for i := range s {
+		if p := IndexPointer[rawStruct](unsafe.Pointer(&s[0]), i); p != &s[i] {
+			t.Errorf("IndexPointer(%d) = %p, want %p", i, p, &s[i])
+		}
+	}
+	b := []byte("abc")
+	if p := IndexPointer[byte](unsafe.Pointer(&b[0]), 2); *p != 'c' {
+		t.Errorf("IndexPointer[byte](2) = %q", *p)
+	}
+}
+
+func TestIsTypedNil(t *testing.T) {
+	var nilPtr *int
+	var nilMap map[string]int
+	var nilChan chan int
+	var nilFunc func()
+	var nilSlice []int
+	var nilUnsafe unsafe.Pointer
+	for _, v := range []any{nilPtr, nilMap, nilChan, nilFunc, nilSlice, nilUnsafe} {
+		if !IsTypedNil(v) {
+			t.Errorf("IsTypedNil(%T(nil)) = false", v)
+		}
+	}
+	x := 1
+	for _, v := range []any{nil, 0, &x, map[string]int{}, []int{}, make(chan int), func() {}, "", struct{}{}} {
+		if IsTypedNil(v) {
+			t.Errorf("IsTypedNil(%#v) = true", v)
+		}
+	}
+}
+
+func TestKindPredicates(t *testing.T) {
+	tests := []struct {
+		v                                   any
+		integer, float, complexNum, numeric bool
+	}{
+		{int8(1), true, false, false, true},
+		{uint64(1), true, false, false, true},
+		{uintptr(1), true, false, false, true},
+		{float32(1), false, true, false, true},
+		{complex128(1), false, false, true, true},
+		{"1", false, false, false, false},
+		{true, false, false, false, false},
+	}
+	for _, test := range tests {
+		if got := IsInteger(test.v); got != test.integer {
+			t.Errorf("IsInteger(%T) = %v", test.v, got)
+		}
+		if got := IsFloat(test.v); got != test.float {
+			t.Errorf("IsFloat(%T) = %v", test.v, got)
+		}
+		if got := IsComplex(test.v); got != test.complexNum {
+			t.Errorf("IsComplex(%T) = %v", test.v, got)
+		}
+		if got := IsNumeric(test.v); got != test.numeric {
+			t.Errorf("IsNumeric(%T) = %v", test.v, got)
+		}
+	}
+}
+
+func TestKindString(t *testing.T) {
+	for k := Kind(0); k <= KindUnsafePointer; k++ {
+		name := k.String()
+		if name != kindNames[k] || name == "" {
+			t.Errorf("Kind(%d).String() = %q", uint8(k), name)
+		}
+	}
+	tests := map[Kind]string{
+		KindInt:                       "int",
+		KindSlice:                     "slice",
+		KindMap:                       "map",
+		KindPointer | KindDirectIface: "ptr|DirectIface",
+		KindArray | KindGCProg:        "array|GCProg",
+		KindStruct | KindDirectIface | KindGCProg: "struct|DirectIface|GCProg",
+		KindMask: "kind31",
+	}
+	for k, want := range tests {
+		if got := k.String(); got != want {
+			t.Errorf("Kind(%d).String() = %q, want %q", uint8(k), got, want)
+		}
+	}
+	if got := GetKind(uint16(0)).String(); got != "uint16" {
+		t.Errorf("GetKind(uint16).String() = %q", got)
+	}
+}
+
+func TestKindSize(t *testing.T) {
+	tests := []struct {
+		kind Kind
+		size uintptr
+		ok   bool
+	}{
+		{KindBool, 1, true},
+		{KindInt16, 2, true},
+		{KindFloat32, 4, true},
+		{KindInt64, 8, true},
+		{KindComplex128, 16, true},
+		{KindUintptr, SystemPointerSize, true},
+		{KindUnsafePointer | KindDirectIface, SystemPointerSize, true},
+		{KindSlice, 0, false},
+		{KindString, 0, false},
+		{KindStruct, 0, false},
+	}
+	for _, test := range tests {
+		if size, ok := test.kind.Size(); size != test.size || ok != test.ok {
+			t.Errorf("%v.Size() = %d, %v", test.kind, size, ok)
+		}
+	}
+	for _, v := range []any{false, int8(0), uint32(0), 0, float64(0), complex64(0)} {
+		if size, ok := GetKind(v).Size(); !ok || size != GetSize(v) {
+			t.Errorf("%v.Size() = %d, %v, want %d", GetKind(v), size, ok, GetSize(v))
+		}
+	}
+}
+
+func TestTypeHasher(t *testing.T) {
+	hasher, ok := TypeHasher("")
+	if !ok {
+		t.Fatal("TypeHasher(string) not ok")
+	}
+	a, b := "hash me", string([]byte("hash me"))
+	const seed = 0x5eed
+	h := hasher(unsafe.Pointer(&a), seed)
+	if hasher(unsafe.Pointer(&a), seed) != h || hasher(unsafe.Pointer(&b), seed) != h {
+		t.Error("TypeHasher hash is not stable for equal values")
+	}
+	other := "hash you"
+	if hasher(unsafe.Pointer(&other), seed) == h {
+		t.Error("TypeHasher hashed different strings the same")
+	}
+	for _, v := range []any{[]int{}, map[int]int{}, func() {}, nil} {
+		if hasher, ok := TypeHasher(v); ok || hasher != nil {
+			t.Errorf("TypeHasher(%T) = %v", v, ok)
+		}
+	}
+}
+
+func TestIsComparable(t *testing.T) {
+	for _, v := range []any{0, struct{ A int }{}, "", &struct{}{}, [2]string{}, any(nil)} {
+		if v != nil && !IsComparable(v) {
+			t.Errorf("IsComparable(%T) = false", v)
+		}
+	}
+	for _, v := range []any{[]int{}, func() {}, map[int]int{}, struct{ S []int }{}, [1]func(){}, nil} {
+		if IsComparable(v) {
+			t.Errorf("IsComparable(%T) = true", v)
+		}
+	}
+}
+
+func TestAssertType(t *testing.T) {
+	if v, ok := AssertType[int](42); !ok || v != 42 {
+		t.Errorf("AssertType[int](42) = %v, %v", v, ok)
+	}
+	if v, ok := AssertType[copyStruct](copyStruct{A: 1, B: "b"}); !ok || v.A != 1 || v.B != "b" {
+		t.Errorf("AssertType[copyStruct] = %+v, %v", v, ok)
+	}
+	x := 5
+	if v, ok := AssertType[*int](&x); !ok || v != &x {
+		t.Errorf("AssertType[*int] = %v, %v", v, ok)
+	}
+	if v, ok := AssertType[int](int32(42)); ok || v != 0 {
+		t.Errorf("AssertType[int](int32) = %v, %v", v, ok)
+	}
+	if v, ok := AssertType[string](nil); ok || v != "" {
+		t.Errorf("AssertType[string](nil) = %q, %v", v, ok)
+	}
+	if _, ok := AssertType[methodInt](1); ok {
+		t.Error("AssertType[methodInt](int) matched")
+	}
+}
+
+var assertSink int
+
+func BenchmarkAssertType(b *testing.B) {
+	var v any = 42
+	for i := 0; i < b.N; i++ {
+		n, _ := AssertType[int](v)
+		assertSink += n
+	}
+}
+
+func BenchmarkBuiltinAssert(b *testing.B) {
+	var v any = 42
+	for i := 0; i < b.N; i++ {
+		n, _ := v.(int)
+		assertSink += n
+	}
+}
+
+func TestForceAssert(t *testing.T) {
+	if v := ForceAssert[int](42); v != 42 {
+		t.Errorf("ForceAssert[int] = %v", v)
+	}
+	if v := ForceAssert[copyStruct](copyStruct{A: 3, B: "c"}); v.A != 3 || v.B != "c" {
+		t.Errorf("ForceAssert[copyStruct] = %+v", v)
+	}
+	x := 5
+	if v := ForceAssert[*int](&x); v != &x {
+		t.Errorf("ForceAssert[*int] = %p, want %p", v, &x)
+	}
+	m := map[string]int{"a": 1}
+	if v := ForceAssert[map[string]int](m); v["a"] != 1 {
+		t.Errorf("ForceAssert[map] = %v", v)
+	}
+}
+
+func TestReinterpret(t *testing.T) {
+	bits := Reinterpret[float64, uint64](1.5)
+	if bits != 0x3FF8000000000000 {
+		t.Errorf("Reinterpret(1.5) = %#x", bits)
+	}
+	if f := Reinterpret[uint64, float64](bits); f != 1.5 {
+		t.Errorf("Reinterpret back = %v", f)
+	}
+	if v := Reinterpret[methodInt, int](7); v != 7 {
+		t.Errorf("Reinterpret[methodInt, int] = %v", v)
+	}
+	if v := Reinterpret[[8]byte, uint64]([8]byte{1, 1, 1, 1, 1, 1, 1, 1}); v != 0x0101010101010101 {
+		t.Errorf("Reinterpret[[8]byte, uint64] = %#x", v)
+	}
+	if b := Reinterpret[uint64, [8]byte](bits); Reinterpret[[8]byte, uint64](b) != bits {
+		t.Errorf("Reinterpret[[8]byte, uint64] round trip = %#x, want %#x", Reinterpret[[8]byte, uint64](b), bits)
+	}
+	expectPanic(t, "Reinterpret between different sizes", func() { Reinterpret[int32, int64](1) })
+}
+
+func TestSliceDataMakeSlice(t *testing.T) {
+	s := make([]int, 3, 5)
+	s[0], s[1], s[2] = 1, 2, 3
+	data := SliceData(s)
+	if data != &s[0] {
+		t.Errorf("SliceData = %p, want %p", data, &s[0])
+	}
+	remade := MakeSlice(data, len(s), cap(s))
+	if len(remade) != len(s) || cap(remade) != cap(s) || &remade[0] != &s[0] {
+		t.Fatalf("MakeSlice gave len %d, cap %d", len(remade), cap(remade))
+	}
+	remade[1] = 20
+	if s[1] != 20 {
+		t.Error("MakeSlice result does not alias the original")
+	}
+	if SliceData(s[:0]) != data || SliceData([]int(nil)) != nil {
+		t.Error("SliceData of an empty or nil slice was wrong")
+	}
+	expectPanic(t, "MakeSlice with cap < len", func() { MakeSlice(data, 3, 2) })
+	expectPanic(t, "MakeSlice with negative len", func() { MakeSlice(data, -1, 2) })
+}
+
+func TestGetTypeFlags(t *testing.T) {
+	if flags := GetTypeFlags(copyStruct{}); flags&TFlagNamed == 0 {
+		t.Errorf("GetTypeFlags(copyStruct) = %#x, missing TFlagNamed", flags)
+	}
+	if flags := GetTypeFlags(struct{ A int }{}); flags&TFlagNamed != 0 {
+		t.Errorf("GetTypeFlags(struct{A int}) = %#x, has TFlagNamed", flags)
+	}
+	if flags := GetTypeFlags(0); flags&TFlagRegularMemory == 0 {
+		t.Errorf("GetTypeFlags(int) = %#x, missing TFlagRegularMemory", flags)
+	}
+	if GetTypeFlags(copyStruct{}) != typeOfValue(copyStruct{}).TypeFlags {
+		t.Error("GetTypeFlags did not return the TypeFlags field")
+	}
+}
+
+func TestIsNamedHasUncommon(t *testing.T) {
+	tests := []struct {
+		v                  any
+		named, hasUncommon bool
+	}{
+		{methodInt(0), true, true},
+		{methodStruct{}, true, true},
+		{copyStruct{}, true, true},
+		{0, true, true},
+		{[]int{}, false, false},
+		{struct{ A int }{}, false, false},
+		{struct{ embeddedInner }{}, false, false},
+		{&struct{ methodInt }{}, false, true},
+	}
+	for _, test := range tests {
+		if got := IsNamed(test.v); got != test.named {
+			t.Errorf("IsNamed(%T) = %v", test.v, got)
+		}
+		if got := HasUncommon(test.v); got != test.hasUncommon {
+			t.Errorf("HasUncommon(%T) = %v", test.v, got)
+		}
+	}
+}
+
+func TestGetPackagePath(t *testing.T) {
+	const self = "github.com/gabe-lee/unsafer"
+	tests := []struct {
+		v    any
+		want string
+	}{
+		{methodMap(nil), self},
+		{methodInt(0), self},
+		{methodStruct{}, self},
+		{Kind(0), self},
+		{reflect.Kind(0), "reflect"},
+		{0, ""},
+		{"", ""},
+		{[]methodInt{}, ""},
+		{&methodStruct{}, ""},
+		{struct{ A int }{}, ""},
+	}
+	for _, test := range tests {
+		if got := GetPackagePath(test.v); got != test.want {
+			t.Errorf("GetPackagePath(%T) = %q, want %q", test.v, got, test.want)
+		}
+	}
+}
+
+func TestFullTypeName(t *testing.T) {
+	for _, v := range []any{0, "", []int{}, map[string]bool{}, [3]uint8{}, struct{ A int }{}, func(int) string { return "" }, (*int)(nil)} {
+		if got, want := FullTypeName(v), reflect.TypeOf(v).String(); got != want {
+			t.Errorf("FullTypeName(%T) = %q, want %q", v, got, want)
+		}
+	}
+	for _, v := range []any{methodMap(nil), methodInt(0), copyStruct{}, Kind(0), reflect.Kind(0), io.SectionReader{}} {
+		typ := reflect.TypeOf(v)
+		if got, want := FullTypeName(v), typ.PkgPath()+"."+typ.Name(); got != want {
+			t.Errorf("FullTypeName(%T) = %q, want %q", v, got, want)
+		}
+	}
+	if got := FullTypeName([]Kind{}); got != "[]unsafer.Kind" {
+		t.Errorf("FullTypeName([]Kind) = %q", got)
+	}
+	if got := FullTypeName(nil); got != "" {
+		t.Errorf("FullTypeName(nil) = %q", got)
+	}
+}
+
+func TestTypeFingerprint(t *testing.T) {
+	sample := []any{
+		0, int8(0), int16(0), int32(0), int64(0), uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		uintptr(0), float32(0), float64(0), complex64(0), complex128(0), "", false,
+		[]int{}, []string{}, [1]int{}, [2]int{}, map[int]int{}, map[string]int{}, make(chan int),
+		func() {}, func(int) {}, (*int)(nil), (*string)(nil), struct{}{}, struct{ A int }{},
+		struct{ B int }{}, methodInt(0), methodMap(nil), methodSlice(nil), methodArray{},
+		methodStruct{}, copyStruct{}, rawStruct{}, fieldsStruct{}, Kind(0), TypeFlag(0),
+	}
+	seen := map[uint64]any{}
+	for _, v := range sample {
+		fp := TypeFingerprint(v)
+		if other, ok := seen[fp]; ok {
+			t.Errorf("TypeFingerprint(%T) = TypeFingerprint(%T) = %#x", v, other, fp)
+		}
+		seen[fp] = v
+		if TypeFingerprint(v) != fp {
+			t.Errorf("TypeFingerprint(%T) is not stable", v)
+		}
+	}
+	if TypeFingerprint(1) != TypeFingerprint(2) {
+		t.Error("TypeFingerprint differs for values of the same type")
+	}
+	if TypeFingerprint(nil) != 0 {
+		t.Error("TypeFingerprint(nil) != 0")
+	}
+}
+
+type gcHolder struct {
+	N    int
+	Data *[64]int
+}
+
+func TestCopyTyped(t *testing.T) {
+	collected := make(chan struct{}, 1)
+	src := &gcHolder{N: 1, Data: new([64]int)}
+	src.Data[63] = 99
+	runtime.SetFinalizer(src.Data, func(*[64]int) { collected <- struct{}{} })
+	dst := new(gcHolder)
+	CopyTyped(unsafe.Pointer(dst), unsafe.Pointer(src), gcHolder{})
+	*src = gcHolder{}
+	for i := 0; i < 5; i++ {
+		garbage := make([][]byte, 1000)
+		for j := range garbage {
+			garbage[j] = make([]byte, 1024)
+		}
+		runtime.GC()
+	}
+	select {
+	case <-collected:
+		t.Fatal("data referenced only by the CopyTyped destination was collected")
+	default:
+	}
+	if dst.N != 1 || dst.Data[63] != 99 {
+		t.Errorf("CopyTyped gave %d, %d", dst.N, dst.Data[63])
+	}
+	a, b := [3]uint16{1, 2, 3}, [3]uint16{}
+	CopyTyped(unsafe.Pointer(&b), unsafe.Pointer(&a), a)
+	if b != a {
+		t.Errorf("CopyTyped of a pointer-free array gave %v", b)
+	}
+	runtime.KeepAlive(dst)
+}
+
+func TestFieldAt(t *testing.T) {
+	s := fieldsStruct{A: 1, B: "second", D: 2.5}
+	v := Box(&s)
+	if p := FieldAt[string](v, unsafe.Offsetof(s.B)); p != &s.B || *p != "second" {
+		t.Errorf("FieldAt(B) = %p (%q), want %p", p, *p, &s.B)
+	}
+	if p := FieldAt[float64](v, unsafe.Offsetof(s.D)); *p != 2.5 {
+		t.Errorf("FieldAt(D) = %v", *p)
+	}
+	if p := FieldAt[byte](s, unsafe.Offsetof(s.A)); *p != 1 {
+		t.Errorf("FieldAt of a copied struct = %v", *p)
+	}
+	expectPanic(t, "FieldAt past the end", func() { FieldAt[int64](v, unsafe.Sizeof(s)-4) })
+	expectPanic(t, "FieldAt of nil", func() { FieldAt[int](nil, 0) })
+}
+
+func TestSetField(t *testing.T) {
+	var s fieldsStruct
+	v := Box(&s)
+	if err := SetField(v, "A", byte(42)); err != nil {
+		t.Fatalf("SetField(A) returned %v", err)
+	}
+	if err := SetField(v, "B", "set"); err != nil {
+		t.Fatalf("SetField(B) returned %v", err)
+	}
+	a, _, _ := FieldPointer(v, "A")
+	b, _, _ := FieldPointer(v, "B")
+	if *(*byte)(a) != 42 || *(*string)(b) != "set" || s.A != 42 || s.B != "set" {
+		t.Errorf("SetField gave A = %d, B = %q", s.A, s.B)
+	}
+	if err := SetField(v, "A", "wrong"); err != ErrTypeMismatch {
+		t.Errorf("SetField with the wrong type returned %v", err)
+	}
+	if err := SetField(v, "Missing", 1); err != ErrFieldNotFound {
+		t.Errorf("SetField of a missing field returned %v", err)
+	}
+	p := new(int)
+	if err := SetField(struct{ P *int }{}, "P", p); err != ErrNotAddressable {
+		t.Errorf("SetField of a direct-iface struct returned %v", err)
+	}
+}
+
+func TestRangeFields(t *testing.T) {
+	s := fieldsStruct{A: 1, B: "b", D: 1.5}
+	var names []string
+	RangeFields(Box(&s), func(name string, value any) bool {
+		names = append(names, name)
+		switch name {
+		case "A":
+			if value != byte(1) {
+				t.Errorf("RangeFields A = %v", value)
+			}
+			*(*byte)(Unbox(value)) = 10
+		case "B":
+			if value != "b" {
+				t.Errorf("RangeFields B = %v", value)
+			}
+			*(*string)(Unbox(value)) = "changed"
+		}
+		return true
+	})
+	if s.A != 10 || s.B != "changed" {
+		t.Errorf("mutation through RangeFields gave A = %d, B = %q", s.A, s.B)
+	}
+	fields := StructFields(s)
+	if len(names) != len(fields) || names[0] != "A" || names[2] != "B" {
+		t.Errorf("RangeFields visited %v", names)
+	}
+	count := 0
+	RangeFields(s, func(string, any) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("RangeFields did not stop early, visited %d", count)
+	}
+}
+
+type deepNode struct {
+	Next  *deepNode
+	Value int
+}
+
+func TestDeepSize(t *testing.T) {
+	str := string([]byte("eleven char"))
+	v := struct {
+		S   []int32
+		Str string
+	}{make([]int32, 3, 5), str}
+	want := unsafe.Sizeof(v) + 5*4 + uintptr(len(str))
+	if got := DeepSize(v); got != want {
+		t.Errorf("DeepSize(struct with slice and string) = %d, want %d", got, want)
+	}
+	a, b := &deepNode{Value: 1}, &deepNode{Value: 2}
+	a.Next, b.Next = b, a
+	if got, want := DeepSize(a), SystemPointerSize+2*unsafe.Sizeof(deepNode{}); got != want {
+		t.Errorf("DeepSize(cycle) = %d, want %d", got, want)
+	}
+	x := new(int)
+	if got, want := DeepSize(struct{ A, B *int }{x, x}), 2*SystemPointerSize+unsafe.Sizeof(0); got != want {
+		t.Errorf("DeepSize(shared pointer) = %d, want %d", got, want)
+	}
+	if got := DeepSize(42); got != unsafe.Sizeof(0) {
+		t.Errorf("DeepSize(int) = %d", got)
+	}
+	if got, min := DeepSize(map[int]int{1: 1}), SystemPointerSize+unsafe.Sizeof(mapHeader{}); got < min {
+		t.Errorf("DeepSize(map) = %d, want at least %d", got, min)
+	}
+	if DeepSize(nil) != 0 {
+		t.Error("DeepSize(nil) != 0")
+	}
+}
+
+func TestTypeIdentical(t *testing.T) {
+	intPtr := TypePointerOf[int]()
+	if !TypeIdentical(0, 0) || !TypeIdentical(intPtr, intPtr) {
+		t.Error("TypeIdentical of equal pointers = false")
+	}
+	if TypeIdentical(intPtr, 0) || TypeIdentical(0, intPtr) || TypeIdentical(intPtr, TypePointerOf[methodInt]()) {
+		t.Error("TypeIdentical of different pointers = true")
+	}
+	if !TypeIdenticalValues(nil, nil) || !TypeIdenticalValues(1, 2) || !TypeIdenticalValues(copyStruct{}, copyStruct{A: 1}) {
+		t.Error("TypeIdenticalValues of identical types = false")
+	}
+	if TypeIdenticalValues(1, nil) || TypeIdenticalValues(nil, 1) || TypeIdenticalValues(1, int64(1)) {
+		t.Error("TypeIdenticalValues of different types = true")
+	}
+}
+
+func TestMapContainsRaw(t *testing.T) {
+	for _, size := range []int{0, 1, 8, 1000} {
+		m := make(map[int]int)
+		for i := 0; i < size; i++ {
+			m[i*3] = i
+		}
+		for k := -3; k < size*3+3; k++ {
+			key := k
+			_, want := m[key]
+			if got := MapContainsRaw(m, unsafe.Pointer(&key)); got != want {
+				t.Fatalf("size %d: MapContainsRaw(%d) = %v, want %v", size, key, got, want)
+			}
+		}
+	}
+	m := map[int]int{1: 1, 2: 2}
+	delete(m, 1)
+	key := 1
+	if MapContainsRaw(m, unsafe.Pointer(&key)) {
+		t.Error("MapContainsRaw found a deleted key")
+	}
+	var nilMap map[int]int
+	if MapContainsRaw(nilMap, unsafe.Pointer(&key)) {
+		t.Error("MapContainsRaw found a key in a nil map")
+	}
+	expectPanic(t, "MapContainsRaw of a slice", func() { MapContainsRaw([]int{}, unsafe.Pointer(&key)) })
+}
+
+type mapGetValue struct {
+	Name  string
+	Count int
+	Pad   [200]byte
+}
+
+func TestMapGetRaw(t *testing.T) {
+	m := map[string]int{"one": 1, "two": 2, "three": 3}
+	for _, k := range []string{"one", "two", "three", "four", ""} {
+		key := k
+		valuePtr, ok := MapGetRaw(m, unsafe.Pointer(&key))
+		want, wantOk := m[key]
+		if ok != wantOk || (ok && *(*int)(valuePtr) != want) || (!ok && valuePtr != nil) {
+			t.Errorf("MapGetRaw(%q) = %v, %v, want %d, %v", key, valuePtr, ok, want, wantOk)
+		}
+	}
+	ms := map[int]mapGetValue{1: {Name: "a", Count: 10}, 2: {Name: "b", Count: 20}}
+	if _, _, _, indirectValue := MapKeyValueSizes(ms); !indirectValue {
+		t.Fatal("map with large values does not store them indirectly")
+	}
+	for k, want := range ms {
+		key := k
+		valuePtr, ok := MapGetRaw(ms, unsafe.Pointer(&key))
+		if !ok || (*mapGetValue)(valuePtr).Name != want.Name || (*mapGetValue)(valuePtr).Count != want.Count {
+			t.Errorf("MapGetRaw(%d) of struct values = %v", key, ok)
+		}
+	}
+	small := map[rawStruct]copyStruct{{A: 1}: {B: "x"}}
+	key := rawStruct{A: 1}
+	if valuePtr, ok := MapGetRaw(small, unsafe.Pointer(&key)); !ok || (*copyStruct)(valuePtr).B != "x" {
+		t.Errorf("MapGetRaw of struct keys and values = %v", ok)
+	}
+}
+
+func TestSpoofChecked(t *testing.T) {
+	v, err := SpoofChecked(methodInt(5), 0)
+	if err != nil || v != 5 {
+		t.Errorf("SpoofChecked(methodInt, int) = %v, %v", v, err)
+	}
+	v, err = SpoofChecked(7, methodInt(0))
+	if err != nil || v != methodInt(7) {
+		t.Errorf("SpoofChecked(int, methodInt) = %v, %v", v, err)
+	}
+	if _, err := SpoofChecked(int32(1), int64(0)); err != ErrIncompatibleTypes {
+		t.Errorf("SpoofChecked between sizes returned %v", err)
+	}
+	x := 1
+	if _, err := SpoofChecked(&x, uintptr(0)); err != ErrIncompatibleTypes {
+		t.Errorf("SpoofChecked between direct and indirect types returned %v", err)
+	}
+	if _, err := SpoofChecked(nil, 0); err != ErrIncompatibleTypes {
+		t.Errorf("SpoofChecked of nil returned %v", err)
+	}
+}
+
+type binaryHeader struct {
+	Magic   uint32
+	Version uint16
+	Flags   uint16
+}
+
+func TestViewAs(t *testing.T) {
+	raw := [12]byte{0xCA, 0xFE, 0xBA, 0xBE, 2, 0, 1, 0}
+	header, ok := ViewAs[binaryHeader](Box(&raw))
+	if !ok {
+		t.Fatal("ViewAs[binaryHeader] of [12]byte not ok")
+	}
+	if header.Magic != *(*uint32)(unsafe.Pointer(&raw[0])) || header.Version != *(*uint16)(unsafe.Pointer(&raw[4])) || header.Flags != *(*uint16)(unsafe.Pointer(&raw[6])) {
+		t.Errorf("ViewAs read header %+v", *header)
+	}
+	header.Flags = 0
+	if raw[6] != 0 {
+		t.Error("ViewAs did not alias the value's storage")
+	}
+	if _, ok := ViewAs[[16]byte](Box(&raw)); ok {
+		t.Error("ViewAs of a larger type was ok")
+	}
+	if _, ok := ViewAs[byte](nil); ok {
+		t.Error("ViewAs of nil was ok")
+	}
+}
+
+func TestRawBytes(t *testing.T) {
+	x := 0x0102030405060708
+	b := RawBytes(Box(&x))
+	if SystemPointerSize == 8 && len(b) != 8 {
+		t.Fatalf("RawBytes(int) has length %d", len(b))
+	}
+	littleEndian := b[0] == 0x08
+	for i := range b {
+		want := byte(len(b) - i)
+		if !littleEndian {
+			want = byte(i + 1 + 8 - len(b))
+		}
+		if b[i] != want {
+			t.Errorf("RawBytes(int) byte %d = %#x, want %#x", i, b[i], want)
+		}
+	}
+	b[0] ^= 0xFF
+	if x == 0x0102030405060708 {
+		t.Error("RawBytes did not alias the value's storage")
+	}
+	if p := RawBytes(&x); len(p) != int(SystemPointerSize) || *(*unsafe.Pointer)(unsafe.Pointer(&p[0])) != unsafe.Pointer(&x) {
+		t.Error("RawBytes of a direct-iface pointer did not hold its address")
+	}
+	if RawBytes(nil) != nil || len(RawBytes(struct{}{})) != 0 {
+		t.Error("RawBytes of nil or an empty struct was not empty")
+	}
+}
+
+func TestReadUint(t *testing.T) {
+	b := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF, 0xFE}
+	for offset := 0; offset <= 1; offset++ {
+		p := unsafe.Pointer(&b[offset])
+		window := b[offset:]
+		if got, want := ReadUint16LE(p), binary.LittleEndian.Uint16(window); got != want {
+			t.Errorf("ReadUint16LE at %d = %#x, want %#x", offset, got, want)
+		}
+		if got, want := ReadUint16BE(p), binary.BigEndian.Uint16(window); got != want {
+			t.Errorf("ReadUint16BE at %d = %#x, want %#x", offset, got, want)
+		}
+		if got, want := ReadUint32LE(p), binary.LittleEndian.Uint32(window); got != want {
+			t.Errorf("ReadUint32LE at %d = %#x, want %#x", offset, got, want)
+		}
+		if got, want := ReadUint32BE(p), binary.BigEndian.Uint32(window); got != want {
+			t.Errorf("ReadUint32BE at %d = %#x, want %#x", offset, got, want)
+		}
+		if got, want := ReadUint64LE(p), binary.LittleEndian.Uint64(window); got != want {
+			t.Errorf("ReadUint64LE at %d = %#x, want %#x", offset, got, want)
+		}
+		if got, want := ReadUint64BE(p), binary.BigEndian.Uint64(window); got != want {
+			t.Errorf("ReadUint64BE at %d = %#x, want %#x", offset, got, want)
+		}
+	}
+}
+
+func TestExtraTypeData(t *testing.T) {
+	typ := typeOfValue([]rawStruct{})
+	p := ExtraTypeData([]rawStruct{})
+	if p != unsafe.Add(unsafe.Pointer(typ), unsafe.Sizeof(TypeInternal{})) {
+		t.Errorf("ExtraTypeData = %p, want just past %p", p, typ)
+	}
+	if elem := *(**TypeInternal)(p); elem != typeAt(TypePointerOf[rawStruct]()) {
+		t.Error("ExtraTypeData of a slice did not begin with its element type")
+	}
+	if elem := *(**TypeInternal)(ExtraTypeData(&copyStruct{})); elem != typeAt(TypePointerOf[copyStruct]()) {
+		t.Error("ExtraTypeData of a pointer did not begin with its element type")
+	}
+	if ExtraTypeData(nil) != nil {
+		t.Error("ExtraTypeData(nil) != nil")
+	}
+}
+
+func TestTypePointerOfConcurrent(t *testing.T) {
+	want := GetTypePointer(0)
+	wantStruct := GetTypePointer(binaryHeader{})
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if TypePointerOf[int]() != want || GetKindOf[int]() != KindInt {
+					t.Error("TypePointerOf[int] differed between goroutines")
+					return
+				}
+				if TypePointerOf[binaryHeader]() != wantStruct || GetKindOf[binaryHeader]() != KindStruct {
+					t.Error("TypePointerOf[binaryHeader] differed between goroutines")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStoredInline(t *testing.T) {
+	x := 1
+	tests := []struct {
+		v    any
+		want bool
+	}{
+		{&x, true},
+		{map[int]int{}, true},
+		{make(chan int), true},
+		{func() {}, true},
+		{unsafe.Pointer(&x), true},
+		{struct{ P *int }{&x}, true},
+		{[1]*int{&x}, true},
+		{int8(1), false},
+		{1, false},
+		{"", false},
+		{pooledValue{}, false},
+		{[2]*int{}, false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if got := StoredInline(test.v); got != test.want {
+			t.Errorf("StoredInline(%T) = %v, want %v", test.v, got, test.want)
+		}
+		if test.v != nil && StoredInline(test.v) != IsDirectIface(test.v) {
+			t.Errorf("StoredInline(%T) disagrees with IsDirectIface", test.v)
+		}
+	}
+}
+
+func TestInventInline(t *testing.T) {
+	x := 5
+	v := InventInline(uintptr(unsafe.Pointer(&x)), TypePointerOf[*int]())
+	if p, ok := v.(*int); !ok || p != &x || *p != 5 {
+		t.Errorf("InventInline(*int) = %v, %v", v, ok)
+	}
+	m := map[string]int{"a": 1}
+	data := *(*uintptr)(unsafe.Pointer(&m))
+	if got, ok := InventInline(data, TypePointerOf[map[string]int]()).(map[string]int); !ok || got["a"] != 1 {
+		t.Errorf("InventInline(map) = %v, %v", got, ok)
+	}
+	if v := InventInline(0, TypePointerOf[*int]()); v.(*int) != nil || !IsTypedNil(v) {
+		t.Error("InventInline of a zero word did not give a typed nil")
+	}
+	expectPanic(t, "InventInline(int)", func() { InventInline(5, TypePointerOf[int]()) })
+}
+
+func TestSprintValue(t *testing.T) {
+	for _, v := range []any{-42, int8(-8), uint16(65535), uintptr(7), 1.5, float32(0.1), 1e21, true, false, "a string", "", complex(1, -2), complex64(3i)} {
+		if got, want := SprintValue(v), fmt.Sprint(v); got != want {
+			t.Errorf("SprintValue(%T) = %q, want %q", v, got, want)
+		}
+	}
+	if got := SprintValue(nil); got != "<nil>" {
+		t.Errorf("SprintValue(nil) = %q", got)
+	}
+	x := 1
+	if got, want := SprintValue(&x), fmt.Sprintf("*int@%p", &x); got != want {
+		t.Errorf("SprintValue(*int) = %q, want %q", got, want)
+	}
+	if got := SprintValue(rawStruct{}); !strings.HasPrefix(got, "unsafer.rawStruct@0x") {
+		t.Errorf("SprintValue(rawStruct) = %q", got)
+	}
+}
+
+func TestNewNode(t *testing.T) {
+	for _, typePointer := range []uintptr{TypePointerOf[rawStruct](), TypePointerOf[[3]uint16](), TypePointerOf[complex128]()} {
+		typ := typeAt(typePointer)
+		dataPtr, node := NewNode(typePointer)
+		if GetTypePointer(node) != typePointer || GetSize(node) != typ.Size || GetAlign(node) != typ.Align {
+			t.Errorf("NewNode(%s) gave a %s node", typ.nameWithoutExtraStar(), typeOfValue(node).nameWithoutExtraStar())
+		}
+		if uintptr(dataPtr)%uintptr(typ.Align) != 0 {
+			t.Errorf("NewNode(%s) = %p, not aligned to %d", typ.nameWithoutExtraStar(), dataPtr, typ.Align)
+		}
+		if Unbox(node) != dataPtr {
+			t.Errorf("NewNode(%s) node does not alias dataPtr", typ.nameWithoutExtraStar())
+		}
+	}
+	dataPtr, node := NewNode(TypePointerOf[rawStruct]())
+	(*rawStruct)(dataPtr).B = 12
+	if node.(rawStruct).B != 12 {
+		t.Error("write through dataPtr not reflected by node")
+	}
+	if _, node := NewNode(TypePointerOf[*int]()); node.(*int) != nil {
+		t.Error("NewNode of a direct-iface type was not zeroed")
+	}
+}
+
+func TestGroupByType(t *testing.T) {
+	values := []any{1, "a", 1.5, 2, "b", 2.5, 3, nil, "c"}
+	groups := GroupByType(values)
+	if len(groups) != 4 {
+		t.Fatalf("GroupByType made %d groups", len(groups))
+	}
+	check := func(typePointer uintptr, want ...any) {
+		t.Helper()
+		group := groups[typePointer]
+		if len(group) != len(want) {
+			t.Fatalf("group %#x = %v, want %v", typePointer, group, want)
+		}
+		for i := range want {
+			if group[i] != want[i] {
+				t.Errorf("group %#x = %v, want %v", typePointer, group, want)
+			}
+		}
+	}
+	check(TypePointerOf[int](), 1, 2, 3)
+	check(TypePointerOf[string](), "a", "b", "c")
+	check(TypePointerOf[float64](), 1.5, 2.5)
+	check(0, nil)
+	if len(GroupByType(nil)) != 0 {
+		t.Error("GroupByType(nil) was not empty")
+	}
+}
+
+type countingWriter struct{ n int }
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	return len(p), nil
+}
+
+func (w *countingWriter) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func TestMethodPointers(t *testing.T) {
+	var rw io.ReadWriter = &countingWriter{}
+	pointers := MethodPointers(&rw)
+	methodCount := len((*ITypeInternal)(unsafe.Pointer(typeAt(TypePointerOf[io.ReadWriter]()))).MethodHeader)
+	if len(pointers) != methodCount || methodCount != 2 {
+		t.Fatalf("MethodPointers gave %d pointers, interface has %d methods", len(pointers), methodCount)
+	}
+	for i, p := range pointers {
+		if p == 0 {
+			t.Errorf("MethodPointers[%d] = 0", i)
+		}
+	}
+	if pointers[0] == pointers[1] {
+		t.Error("MethodPointers gave the same pointer for Read and Write")
+	}
+	var w io.Writer
+	if MethodPointers(&w) != nil {
+		t.Error("MethodPointers of a nil interface variable was not nil")
+	}
+	var empty any = 1
+	if MethodPointers(&empty) != nil {
+		t.Error("MethodPointers of an empty interface was not nil")
+	}
+	expectPanic(t, "MethodPointers of an interface value", func() { MethodPointers(rw) })
+}
+
+func TestImplements(t *testing.T) {
+	writer := (*ITypeInternal)(unsafe.Pointer(typeAt(TypePointerOf[io.Writer]())))
+	if !Implements(&countingWriter{}, writer) {
+		t.Error("Implements(*countingWriter, io.Writer) = false")
+	}
+	if Implements(countingWriter{}, writer) {
+		t.Error("Implements(countingWriter, io.Writer) = true")
+	}
+	if Implements(1, writer) || Implements(nil, writer) {
+		t.Error("Implements of int or nil with io.Writer = true")
+	}
+	empty := (*ITypeInternal)(unsafe.Pointer(typeAt(TypePointerOf[any]())))
+	if !Implements(1, empty) || !Implements(countingWriter{}, empty) {
+		t.Error("Implements with the empty interface = false")
+	}
+	var w io.Writer = &countingWriter{}
+	if _, ok := w.(io.Reader); ok != Implements(w, (*ITypeInternal)(unsafe.Pointer(typeAt(TypePointerOf[io.Reader]())))) {
+		t.Error("Implements disagrees with a type assertion")
+	}
+}